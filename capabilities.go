@@ -0,0 +1,217 @@
+package govfd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/corrreia/govfd/types"
+)
+
+// statusTimeout bounds the wait for each part of a status reply.
+const statusTimeout = 500 * time.Millisecond
+
+// SetReverse turns reverse (dark on lit) characters on or off for the text
+// written afterwards. It returns an error wrapping ErrUnsupported if the
+// protocol has no reverse mode.
+func (d *Display) SetReverse(enabled bool) error {
+	if d.protocol == nil {
+		return errors.New("no command protocol set")
+	}
+	protocol, ok := d.protocol.(ReverseProtocol)
+	if !ok {
+		return unsupported(d.protocol, "reverse mode")
+	}
+	cmd, err := protocol.SetReverse(enabled)
+	if err != nil {
+		return err
+	}
+	return d.writeBytes(cmd)
+}
+
+// SetDisplayMode selects what happens when text reaches the end of the
+// screen: overwrite from the top, or scroll vertically or horizontally. It
+// returns an error wrapping ErrUnsupported if the protocol cannot change it.
+func (d *Display) SetDisplayMode(mode types.DisplayMode) error {
+	if d.protocol == nil {
+		return errors.New("no command protocol set")
+	}
+	protocol, ok := d.protocol.(DisplayModeProtocol)
+	if !ok {
+		return unsupported(d.protocol, "display modes")
+	}
+	cmd, err := protocol.SetDisplayMode(mode)
+	if err != nil {
+		return err
+	}
+	return d.writeBytes(cmd)
+}
+
+// SetClock sets the display's built-in clock to hour:minute and shows it.
+// It returns an error wrapping ErrUnsupported if the display has no clock.
+func (d *Display) SetClock(hour, minute int) error {
+	if d.protocol == nil {
+		return errors.New("no command protocol set")
+	}
+	protocol, ok := d.protocol.(ClockProtocol)
+	if !ok {
+		return unsupported(d.protocol, "clock")
+	}
+	return d.writeClockCommand(protocol.SetClock(hour, minute))
+}
+
+// ShowClock shows the display's built-in clock. It returns an error wrapping
+// ErrUnsupported if the display has no clock.
+func (d *Display) ShowClock() error {
+	if d.protocol == nil {
+		return errors.New("no command protocol set")
+	}
+	protocol, ok := d.protocol.(ClockProtocol)
+	if !ok {
+		return unsupported(d.protocol, "clock")
+	}
+	return d.writeClockCommand(protocol.ShowClock())
+}
+
+// writeClockCommand sends a clock command. The display positions the clock
+// itself, so the cursor position is unknown afterwards.
+func (d *Display) writeClockCommand(cmd []byte, err error) error {
+	if err != nil {
+		return err
+	}
+	if err := d.writeBytes(cmd); err != nil {
+		return err
+	}
+	d.cursorColumn, d.cursorRow = 0, 0
+	return nil
+}
+
+// GetStatus asks the display for its status and waits for the reply. It
+// returns an error wrapping ErrUnsupported if the protocol cannot report
+// status.
+func (d *Display) GetStatus() (*types.Status, error) {
+	if d.protocol == nil {
+		return nil, errors.New("no command protocol set")
+	}
+	protocol, ok := d.protocol.(StatusProtocol)
+	if !ok {
+		return nil, unsupported(d.protocol, "status")
+	}
+	if d.port == nil {
+		return nil, errors.New("display is not open")
+	}
+	cmd, err := protocol.RequestStatus()
+	if err != nil {
+		return nil, err
+	}
+	if err := d.port.ResetInputBuffer(); err != nil {
+		return nil, err
+	}
+	if err := d.port.SetReadTimeout(statusTimeout); err != nil {
+		return nil, err
+	}
+	if err := d.writeBytes(cmd); err != nil {
+		return nil, err
+	}
+	var reply []byte
+	buf := make([]byte, 64)
+	for {
+		n, err := d.port.Read(buf)
+		if err != nil {
+			return nil, err
+		}
+		if n == 0 {
+			return nil, fmt.Errorf("status reply incomplete after %d bytes", len(reply))
+		}
+		reply = append(reply, buf[:n]...)
+		status, err := protocol.ParseStatus(reply)
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			continue
+		}
+		return status, err
+	}
+}
+
+// WriteLine replaces a whole row with text, padded with spaces to the
+// display width, in a single hardware command. The cursor position is
+// unknown afterwards, unless the protocol emulates the cursor. It returns an
+// error wrapping ErrUnsupported if the protocol cannot upload lines.
+func (d *Display) WriteLine(row int, text string) error {
+	if d.protocol == nil {
+		return errors.New("no command protocol set")
+	}
+	protocol, ok := d.protocol.(LineUploadProtocol)
+	if !ok {
+		return unsupported(d.protocol, "line upload")
+	}
+	if row < 1 || (d.rows > 0 && row > d.rows) {
+		return errors.New("row exceeds configured height")
+	}
+	encoded, cells, err := d.encodeMessage(text)
+	if err != nil {
+		return err
+	}
+	if d.columns > 0 {
+		if cells > d.columns {
+			return errors.New("text exceeds configured width")
+		}
+		for ; cells < d.columns; cells++ {
+			encoded = append(encoded, ' ')
+		}
+	}
+	cmd, err := protocol.UploadLine(row, encoded)
+	if err != nil {
+		return err
+	}
+	if err := d.writeCommand(cmd); err != nil {
+		return err
+	}
+	d.syncCursor()
+	return nil
+}
+
+// Marquee makes the display scroll text continuously by itself, leaving the
+// host free. It returns an error wrapping ErrUnsupported if the protocol has
+// no hardware marquee.
+func (d *Display) Marquee(text string) error {
+	if d.protocol == nil {
+		return errors.New("no command protocol set")
+	}
+	protocol, ok := d.protocol.(MarqueeProtocol)
+	if !ok {
+		return unsupported(d.protocol, "marquee")
+	}
+	encoded, _, err := d.encodeMessage(text)
+	if err != nil {
+		return err
+	}
+	cmd, err := protocol.Marquee(encoded)
+	if err != nil {
+		return err
+	}
+	if err := d.writeCommand(cmd); err != nil {
+		return err
+	}
+	d.syncCursor()
+	return nil
+}
+
+// syncCursor takes the cursor position from a protocol that emulates the
+// cursor, and otherwise marks it unknown.
+func (d *Display) syncCursor() {
+	d.cursorColumn, d.cursorRow = 0, 0
+	if protocol, ok := d.protocol.(ShadowProtocol); ok {
+		d.cursorColumn, d.cursorRow = protocol.Cursor()
+	}
+}
+
+// encodeMessage encodes text for a protocol command that carries it, under
+// the encoding policy, returning the bytes and the cells they occupy.
+// Charset switches are sent ahead of the command.
+func (d *Display) encodeMessage(text string) ([]byte, int, error) {
+	if err := d.checkEncodingPolicy(text); err != nil {
+		return nil, 0, err
+	}
+	return d.smartEncodeText(text)
+}
@@ -0,0 +1,187 @@
+package govfd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/corrreia/govfd/types"
+)
+
+// basicProtocol has the core commands only, and no cursor blink.
+type basicProtocol struct{}
+
+func (p *basicProtocol) GetName() string           { return "BASIC" }
+func (p *basicProtocol) GetDescription() string    { return "Core commands only" }
+func (p *basicProtocol) Clear() ([]byte, error)    { return []byte{0x01}, nil }
+func (p *basicProtocol) FormFeed() ([]byte, error) { return []byte{0x0C}, nil }
+func (p *basicProtocol) SetBrightness(level int) ([]byte, error) {
+	return []byte{0x02, byte(level)}, nil
+}
+func (p *basicProtocol) SetCharset(page int) ([]byte, error) { return []byte{0x03, byte(page)}, nil }
+func (p *basicProtocol) SelfTest() ([]byte, error)           { return []byte{0x04}, nil }
+
+func (p *basicProtocol) MoveCursor(column, row int) ([]byte, error) {
+	return []byte{0x10, byte(column), byte(row)}, nil
+}
+
+func (p *basicProtocol) SetBlink(intervalMs int) ([]byte, error) {
+	return nil, fmt.Errorf("cursor blink: %w", types.ErrUnsupported)
+}
+
+func TestOptionalCapabilitiesESCPOS(t *testing.T) {
+	tests := []struct {
+		name string
+		call func(d *Display) error
+		want []byte
+	}{
+		{"reverse on", func(d *Display) error { return d.SetReverse(true) }, []byte{0x1F, 0x72, 0x01}},
+		{"reverse off", func(d *Display) error { return d.SetReverse(false) }, []byte{0x1F, 0x72, 0x00}},
+		{"vertical scroll", func(d *Display) error { return d.SetDisplayMode(types.DisplayModeVerticalScroll) }, []byte{0x1F, 0x02}},
+		{"set clock", func(d *Display) error { return d.SetClock(13, 45) }, []byte{0x1F, 0x54, 13, 45}},
+		{"show clock", func(d *Display) error { return d.ShowClock() }, []byte{0x1F, 0x55}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, port := newTestDisplay(20, 2)
+			if err := tt.call(d); err != nil {
+				t.Fatalf("error: %v", err)
+			}
+			if string(port.written) != string(tt.want) {
+				t.Errorf("wrote % X, want % X", port.written, tt.want)
+			}
+		})
+	}
+}
+
+func TestInvalidArgumentsSendNothing(t *testing.T) {
+	d, port := newTestDisplay(20, 2)
+	if err := d.SetBrightness(5); err == nil {
+		t.Error("SetBrightness(5) succeeded")
+	}
+	if err := d.SetClock(24, 0); err == nil {
+		t.Error("SetClock(24, 0) succeeded")
+	}
+	if err := d.SetDisplayMode("sideways"); err == nil {
+		t.Error("SetDisplayMode(unknown) succeeded")
+	}
+	if len(port.written) != 0 {
+		t.Errorf("wrote % X for invalid arguments", port.written)
+	}
+	if d.GetBrightness() != 0 {
+		t.Errorf("brightness = %d after rejected level", d.GetBrightness())
+	}
+}
+
+func TestClockForgetsCursor(t *testing.T) {
+	d, port := newTestDisplay(20, 2)
+	d.SetCursor(3, 2)
+	d.ShowClock()
+	port.written = nil
+
+	if err := d.SetCursor(3, 2); err != nil {
+		t.Fatalf("SetCursor error: %v", err)
+	}
+	if len(port.written) == 0 {
+		t.Error("SetCursor after the clock was skipped as a no-op")
+	}
+}
+
+func TestUnsupportedCapabilities(t *testing.T) {
+	d, port := newTestDisplay(20, 2)
+	d.protocol = &basicProtocol{}
+
+	calls := map[string]func() error{
+		"SetReverse":     func() error { return d.SetReverse(true) },
+		"SetDisplayMode": func() error { return d.SetDisplayMode(types.DisplayModeOverwrite) },
+		"SetClock":       func() error { return d.SetClock(12, 0) },
+		"ShowClock":      func() error { return d.ShowClock() },
+		"GetStatus":      func() error { _, err := d.GetStatus(); return err },
+		"DefineGlyph":    func() error { return d.DefineGlyph('→', types.Glyph{}) },
+		"SetBlink":       func() error { return d.SetBlink(500) },
+	}
+	for name, call := range calls {
+		if err := call(); !errors.Is(err, ErrUnsupported) {
+			t.Errorf("%s error = %v, want ErrUnsupported", name, err)
+		}
+	}
+	if len(port.written) != 0 {
+		t.Errorf("wrote % X for unsupported operations", port.written)
+	}
+
+	if err := d.SetBrightness(2); err != nil {
+		t.Errorf("SetBrightness error: %v", err)
+	}
+}
+
+func TestUnsupportedCharsetSwitch(t *testing.T) {
+	d, port := newTestDisplay(20, 2)
+	d.protocol = &basicProtocol{}
+	port.written = nil
+
+	err := d.switcher().SelectInternationalCharset(types.InternationalJapan)
+	if !errors.Is(err, ErrUnsupported) {
+		t.Errorf("SelectInternationalCharset error = %v, want ErrUnsupported", err)
+	}
+	if err := d.switcher().SetDoubleByteMode(true); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("SetDoubleByteMode error = %v, want ErrUnsupported", err)
+	}
+	if len(port.written) != 0 {
+		t.Errorf("wrote % X for unsupported switches", port.written)
+	}
+}
+
+// statusProtocol replies to ENQ with two bytes: a header and a fault bit.
+type statusProtocol struct {
+	basicProtocol
+}
+
+func (p *statusProtocol) RequestStatus() ([]byte, error) { return []byte{0x05}, nil }
+
+func (p *statusProtocol) ParseStatus(reply []byte) (*types.Status, error) {
+	if len(reply) < 2 {
+		return nil, fmt.Errorf("status reply: %w", io.ErrUnexpectedEOF)
+	}
+	if reply[0] != 0x06 {
+		return nil, fmt.Errorf("bad status header %#02x", reply[0])
+	}
+	fault := reply[1]&0x01 != 0
+	return &types.Status{Ready: !fault, Fault: fault, Raw: reply[:2]}, nil
+}
+
+// replyPort returns its replies one Read at a time, then times out.
+type replyPort struct {
+	mockPort
+	replies [][]byte
+}
+
+func (p *replyPort) Read(b []byte) (int, error) {
+	if len(p.replies) == 0 {
+		return 0, nil
+	}
+	n := copy(b, p.replies[0])
+	p.replies = p.replies[1:]
+	return n, nil
+}
+
+func TestGetStatus(t *testing.T) {
+	port := &replyPort{replies: [][]byte{{0x06}, {0x01}}}
+	d := &Display{port: port, protocol: &statusProtocol{}}
+
+	status, err := d.GetStatus()
+	if err != nil {
+		t.Fatalf("GetStatus error: %v", err)
+	}
+	if status.Ready || !status.Fault {
+		t.Errorf("status = %+v, want a fault", status)
+	}
+	if string(port.written) != "\x05" {
+		t.Errorf("wrote % X, want 05", port.written)
+	}
+
+	port.replies = [][]byte{{0x06}}
+	if _, err := d.GetStatus(); err == nil {
+		t.Error("GetStatus succeeded on a truncated reply")
+	}
+}
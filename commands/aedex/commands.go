@@ -0,0 +1,65 @@
+// Package aedex implements the Aedex line command protocol of legacy pole
+// displays.
+package aedex
+
+import (
+	"github.com/corrreia/govfd/commands/internal/shadow"
+	"github.com/corrreia/govfd/types"
+)
+
+// AedexProtocol implements the Protocol interface for Aedex displays. The
+// protocol only replaces whole lines, so the embedded shadow.LineProtocol
+// emulates cursor addressing. Each display gets its own instance from
+// ForModel; the zero value emulates a 20x2 screen.
+type AedexProtocol struct {
+	shadow.LineProtocol[lines]
+}
+
+// lines builds Aedex line commands.
+type lines struct{}
+
+// Line builds the command showing text on a 1-based row.
+func (lines) Line(row int, text []byte) []byte {
+	if row == 1 {
+		return BuildLineSeq(LineUpper, text)
+	}
+	return BuildLineSeq(LineLower, text)
+}
+
+// ForModel returns a protocol with its own shadow screen of the model's size.
+func (p *AedexProtocol) ForModel(profile *types.ModelProfile) (types.Protocol, error) {
+	sized, err := p.Sized(profile)
+	if err != nil {
+		return nil, err
+	}
+	return &AedexProtocol{LineProtocol: sized}, nil
+}
+
+// GetName returns the protocol name.
+func (p *AedexProtocol) GetName() string {
+	return types.ProtocolAedex
+}
+
+// GetDescription returns the protocol description.
+func (p *AedexProtocol) GetDescription() string {
+	return "Aedex line commands, with emulated cursor addressing"
+}
+
+// Clear returns the command sequence blanking the screen (emulated).
+func (p *AedexProtocol) Clear() ([]byte, error) {
+	return p.blank(), nil
+}
+
+// FormFeed returns the command sequence blanking the screen (emulated).
+func (p *AedexProtocol) FormFeed() ([]byte, error) {
+	return p.blank(), nil
+}
+
+// blank resets the shadow screen and returns the command showing it.
+func (p *AedexProtocol) blank() []byte {
+	rows := p.ResetScreen()
+	if len(rows) == 2 {
+		return BuildLineSeq(LineBoth, append(append([]byte{}, rows[0]...), rows[1]...))
+	}
+	return lines{}.Line(1, rows[0])
+}
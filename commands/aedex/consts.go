@@ -0,0 +1,34 @@
+package aedex
+
+// Aedex command constants. Aedex displays take whole lines of text as
+// "!#n text CR" commands; there is no cursor addressing.
+
+const (
+	// Command prefix: "!#"
+	CmdPrefix1 = 0x21 // !
+	CmdPrefix2 = 0x23 // #
+
+	// Carriage Return - terminates every command
+	CmdCarriageReturn = 0x0D
+)
+
+// Line selectors (following "!#")
+const (
+	// 1 - Upper line
+	LineUpper = 0x31 // 1
+
+	// 2 - Lower line
+	LineLower = 0x32 // 2
+
+	// 4 - Both lines, upper then lower
+	LineBoth = 0x34 // 4
+)
+
+// BuildLineSeq creates the command sequence showing text on the selected line.
+// Returns: ! # selector text CR
+func BuildLineSeq(selector byte, text []byte) []byte {
+	seq := make([]byte, 0, len(text)+4)
+	seq = append(seq, CmdPrefix1, CmdPrefix2, selector)
+	seq = append(seq, text...)
+	return append(seq, CmdCarriageReturn)
+}
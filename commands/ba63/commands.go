@@ -0,0 +1,66 @@
+// Package ba63 implements the ANSI-style command set of Wincor Nixdorf BA63
+// and similar retail displays.
+package ba63
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/corrreia/govfd/types"
+)
+
+// BA63Protocol implements the Protocol interface for BA63 displays.
+type BA63Protocol struct{}
+
+// GetName returns the protocol name.
+func (p *BA63Protocol) GetName() string {
+	return types.ProtocolBA63
+}
+
+// GetDescription returns the protocol description.
+func (p *BA63Protocol) GetDescription() string {
+	return "ANSI-style command set of Wincor Nixdorf BA63 displays"
+}
+
+// Clear returns the command sequence to erase the display and restore code
+// page 437. The display has no reset command.
+func (p *BA63Protocol) Clear() ([]byte, error) {
+	return SeqInitialize, nil
+}
+
+// FormFeed returns the command sequence to clear screen content.
+func (p *BA63Protocol) FormFeed() ([]byte, error) {
+	return SeqEraseDisplay, nil
+}
+
+// MoveCursor returns the command sequence to move cursor to position (1-based).
+func (p *BA63Protocol) MoveCursor(column, row int) ([]byte, error) {
+	if column < 1 || column > 99 || row < 1 || row > 99 {
+		return nil, errors.New("column/row must be between 1 and 99")
+	}
+	return BuildCursorPositionSeq(column, row), nil
+}
+
+// SetBrightness reports that BA63 has fixed brightness.
+func (p *BA63Protocol) SetBrightness(level int) ([]byte, error) {
+	return nil, fmt.Errorf("brightness: %w", types.ErrUnsupported)
+}
+
+// SetBlink reports that BA63 has no cursor blink period.
+func (p *BA63Protocol) SetBlink(intervalMs int) ([]byte, error) {
+	return nil, fmt.Errorf("cursor blink: %w", types.ErrUnsupported)
+}
+
+// SetCharset returns the command sequence selecting a code table by its
+// country code (ESC R n, n >= 0x30).
+func (p *BA63Protocol) SetCharset(page int) ([]byte, error) {
+	if page < CountryPC437 || page > 0x7F {
+		return nil, fmt.Errorf("code table country code must be between %#02x and 0x7F", CountryPC437)
+	}
+	return BuildCountrySeq(byte(page)), nil
+}
+
+// SelfTest reports that BA63 has no self-test command.
+func (p *BA63Protocol) SelfTest() ([]byte, error) {
+	return nil, fmt.Errorf("self-test: %w", types.ErrUnsupported)
+}
@@ -0,0 +1,34 @@
+package ba63
+
+import "testing"
+
+func TestMoveCursorDecimalParameters(t *testing.T) {
+	p := &BA63Protocol{}
+	tests := []struct {
+		column, row int
+		want        string
+	}{
+		{1, 1, "\x1b[1;1H"},
+		{20, 2, "\x1b[2;20H"},
+		{40, 4, "\x1b[4;40H"},
+	}
+	for _, tt := range tests {
+		got, err := p.MoveCursor(tt.column, tt.row)
+		if err != nil || string(got) != tt.want {
+			t.Errorf("MoveCursor(%d, %d) = %q, %v; want %q", tt.column, tt.row, got, err, tt.want)
+		}
+	}
+	if _, err := p.MoveCursor(0, 1); err == nil {
+		t.Error("MoveCursor(0, 1) succeeded")
+	}
+}
+
+func TestSetCharsetCountryCodes(t *testing.T) {
+	p := &BA63Protocol{}
+	if got, err := p.SetCharset(CountryPC850); err != nil || string(got) != "\x1bR\x31" {
+		t.Errorf("SetCharset(PC850) = % X, %v", got, err)
+	}
+	if _, err := p.SetCharset(0x03); err == nil {
+		t.Error("SetCharset accepted a national variant as a code table")
+	}
+}
@@ -0,0 +1,69 @@
+package ba63
+
+// BA63 command byte constants. Wincor Nixdorf BA63 and similar retail
+// displays use ANSI (VT100-like) control sequences with decimal parameters.
+
+// Escape Sequence Prefixes
+const (
+	// Escape character - used as prefix for all commands
+	CmdEscape = 0x1B // ESC
+
+	// Control Sequence Introducer - ESC [ starts ANSI sequences
+	CmdCSI = 0x5B // [
+)
+
+// Escape Sequence Commands
+const (
+	// ESC R - Select country code (followed by n)
+	CmdEscCountry = 0x52 // R
+
+	// ESC [ row ; col H - Position cursor
+	CmdCSICursorPosition = 0x48 // H
+
+	// ESC [ n J - Erase in display (2 = whole display)
+	CmdCSIEraseDisplay = 0x4A // J
+
+	// Parameter separator of ANSI sequences
+	CmdCSISeparator = 0x3B // ;
+)
+
+// Country codes 0x30 and above select whole code tables.
+const (
+	CountryPC437 = 0x30
+	CountryPC850 = 0x31
+	CountryPC858 = 0x34
+)
+
+// Complete Command Sequences as byte arrays for convenience
+var (
+	// Erase the whole display: ESC [ 2 J
+	SeqEraseDisplay = []byte{CmdEscape, CmdCSI, '2', CmdCSIEraseDisplay}
+
+	// Initialize: erase the display and select code page 437, the
+	// power-on state
+	SeqInitialize = []byte{CmdEscape, CmdCSI, '2', CmdCSIEraseDisplay, CmdEscape, CmdEscCountry, CountryPC437}
+)
+
+// BuildCursorPositionSeq creates the command sequence to position the cursor.
+// Returns: ESC [ row ; column H (decimal digits)
+func BuildCursorPositionSeq(column, row int) []byte {
+	seq := []byte{CmdEscape, CmdCSI}
+	seq = appendDecimal(seq, row)
+	seq = append(seq, CmdCSISeparator)
+	seq = appendDecimal(seq, column)
+	return append(seq, CmdCSICursorPosition)
+}
+
+// BuildCountrySeq creates the command sequence to select a country code.
+// Returns: ESC R n
+func BuildCountrySeq(n byte) []byte {
+	return []byte{CmdEscape, CmdEscCountry, n}
+}
+
+// appendDecimal appends the decimal digits of n.
+func appendDecimal(seq []byte, n int) []byte {
+	if n >= 10 {
+		seq = appendDecimal(seq, n/10)
+	}
+	return append(seq, byte('0'+n%10))
+}
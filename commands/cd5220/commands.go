@@ -0,0 +1,117 @@
+// Package cd5220 implements the CD5220 command set of generic pole displays.
+package cd5220
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/corrreia/govfd/types"
+)
+
+// CD5220Protocol implements the Protocol interface for CD5220 displays.
+type CD5220Protocol struct{}
+
+// GetName returns the protocol name.
+func (p *CD5220Protocol) GetName() string {
+	return types.ProtocolCD5220
+}
+
+// GetDescription returns the protocol description.
+func (p *CD5220Protocol) GetDescription() string {
+	return "CD5220 command set for generic pole displays"
+}
+
+// Clear returns the command sequence to initialize the display.
+func (p *CD5220Protocol) Clear() ([]byte, error) {
+	return SeqInitialize, nil
+}
+
+// FormFeed returns the command sequence to clear screen content.
+func (p *CD5220Protocol) FormFeed() ([]byte, error) {
+	return SeqClearScreen, nil
+}
+
+// MoveCursor returns the command sequence to move cursor to position (1-based).
+func (p *CD5220Protocol) MoveCursor(column, row int) ([]byte, error) {
+	if column < 1 || column > 255 || row < 1 || row > 255 {
+		return nil, errors.New("column/row must be between 1 and 255")
+	}
+	return BuildMoveCursorSeq(byte(column), byte(row)), nil
+}
+
+// SetBrightness returns the command sequence to set brightness level.
+func (p *CD5220Protocol) SetBrightness(level int) ([]byte, error) {
+	if level < 1 || level > 4 {
+		return nil, errors.New("brightness level must be between 1 and 4")
+	}
+	return BuildBrightnessSeq(byte(level)), nil
+}
+
+// SetBlink reports that CD5220 has no cursor blink period.
+func (p *CD5220Protocol) SetBlink(intervalMs int) ([]byte, error) {
+	return nil, fmt.Errorf("cursor blink: %w", types.ErrUnsupported)
+}
+
+// SetCharset returns the command sequence to set character encoding table.
+func (p *CD5220Protocol) SetCharset(page int) ([]byte, error) {
+	if page < 0 || page > 255 {
+		return nil, errors.New("page must be between 0 and 255")
+	}
+	return BuildCharsetSeq(byte(page)), nil
+}
+
+// SetDisplayMode returns the command sequence to select overwrite, vertical
+// scroll or horizontal scroll mode.
+func (p *CD5220Protocol) SetDisplayMode(mode types.DisplayMode) ([]byte, error) {
+	switch mode {
+	case types.DisplayModeOverwrite:
+		return []byte{CmdEscape, CmdEscOverwriteMode}, nil
+	case types.DisplayModeVerticalScroll:
+		return []byte{CmdEscape, CmdEscVerticalScrollMode}, nil
+	case types.DisplayModeHorizontalScroll:
+		return []byte{CmdEscape, CmdEscHorizontalScrollMode}, nil
+	}
+	return nil, fmt.Errorf("unknown display mode %q", mode)
+}
+
+// UploadLine returns the command sequence replacing row 1 (ESC Q A) or
+// row 2 (ESC Q B) with already encoded text.
+func (p *CD5220Protocol) UploadLine(row int, text []byte) ([]byte, error) {
+	if err := checkMessage(text); err != nil {
+		return nil, err
+	}
+	switch row {
+	case 1:
+		return BuildMessageSeq(MsgUpperLine, text), nil
+	case 2:
+		return BuildMessageSeq(MsgLowerLine, text), nil
+	}
+	return nil, errors.New("line upload row must be 1 or 2")
+}
+
+// Marquee returns the command sequence scrolling already encoded text
+// continuously across the upper line (ESC Q D).
+func (p *CD5220Protocol) Marquee(text []byte) ([]byte, error) {
+	if len(text) > MaxMarqueeLength {
+		return nil, fmt.Errorf("marquee text must be at most %d bytes", MaxMarqueeLength)
+	}
+	if err := checkMessage(text); err != nil {
+		return nil, err
+	}
+	return BuildMessageSeq(MsgMarquee, text), nil
+}
+
+// SelfTest reports that CD5220 has no self-test command.
+func (p *CD5220Protocol) SelfTest() ([]byte, error) {
+	return nil, fmt.Errorf("self-test: %w", types.ErrUnsupported)
+}
+
+// checkMessage rejects control codes, which would end a message early.
+func checkMessage(text []byte) error {
+	for _, b := range text {
+		if b < 0x20 {
+			return fmt.Errorf("message contains control code %#02x", b)
+		}
+	}
+	return nil
+}
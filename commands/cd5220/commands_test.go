@@ -0,0 +1,34 @@
+package cd5220
+
+import "testing"
+
+func TestUploadLineSelectsRow(t *testing.T) {
+	p := &CD5220Protocol{}
+	got, err := p.UploadLine(1, []byte("Hello"))
+	if err != nil || string(got) != "\x1bQAHello\r" {
+		t.Errorf("UploadLine(1) = %q, %v; want %q", got, err, "\x1bQAHello\r")
+	}
+	if _, err := p.UploadLine(3, []byte("Hello")); err == nil {
+		t.Error("UploadLine(3) succeeded")
+	}
+}
+
+func TestMessagesRejectControlCodes(t *testing.T) {
+	p := &CD5220Protocol{}
+	if _, err := p.UploadLine(1, []byte("Hi\rthere")); err == nil {
+		t.Error("UploadLine accepted an embedded CR")
+	}
+	if _, err := p.Marquee([]byte("\x1b@")); err == nil {
+		t.Error("Marquee accepted an embedded ESC")
+	}
+}
+
+func TestMoveCursorRange(t *testing.T) {
+	p := &CD5220Protocol{}
+	if got, err := p.MoveCursor(1, 2); err != nil || string(got) != "\x1bl\x01\x02" {
+		t.Errorf("MoveCursor(1, 2) = % X, %v", got, err)
+	}
+	if _, err := p.MoveCursor(0, 1); err == nil {
+		t.Error("MoveCursor(0, 1) succeeded")
+	}
+}
@@ -0,0 +1,95 @@
+package cd5220
+
+// CD5220 command byte constants. The CD5220 command set is used by most
+// generic pole displays; commands are ESC-prefixed and line messages are
+// terminated by CR.
+
+// ASCII Control Characters
+const (
+	// Form Feed (CLR) - clears the screen
+	CmdClearScreen = 0x0C
+
+	// Carriage Return - terminates line upload and marquee messages
+	CmdCarriageReturn = 0x0D
+
+	// Escape character - used as prefix for all commands
+	CmdEscape = 0x1B // ESC
+)
+
+// Escape Sequence Commands (ESC + command)
+const (
+	// ESC @ - Initialize display
+	CmdEscInitialize = 0x40 // @
+
+	// ESC l - Move cursor (followed by column, row)
+	CmdEscMoveCursor = 0x6C // l
+
+	// ESC * - Set brightness (followed by level 1-4)
+	CmdEscBrightness = 0x2A // *
+
+	// ESC t - Select character code table (followed by n)
+	CmdEscCharsetTable = 0x74 // t
+
+	// ESC Q - Line message commands (followed by A, B or D)
+	CmdEscMessage = 0x51 // Q
+
+	// ESC DC1 - Overwrite mode
+	CmdEscOverwriteMode = 0x11 // DC1
+
+	// ESC DC2 - Vertical scroll mode
+	CmdEscVerticalScrollMode = 0x12 // DC2
+
+	// ESC DC3 - Horizontal scroll mode
+	CmdEscHorizontalScrollMode = 0x13 // DC3
+)
+
+// Line message selectors (ESC Q + selector)
+const (
+	// A - Upload the upper line
+	MsgUpperLine = 0x41 // A
+
+	// B - Upload the lower line
+	MsgLowerLine = 0x42 // B
+
+	// D - Scroll a message continuously on the upper line
+	MsgMarquee = 0x44 // D
+)
+
+// MaxMarqueeLength is the longest message ESC Q D accepts.
+const MaxMarqueeLength = 40
+
+// Complete Command Sequences as byte arrays for convenience
+var (
+	// Initialize display: ESC @
+	SeqInitialize = []byte{CmdEscape, CmdEscInitialize}
+
+	// Clear screen: CLR
+	SeqClearScreen = []byte{CmdClearScreen}
+)
+
+// BuildMoveCursorSeq creates the command sequence to move the cursor.
+// Returns: ESC l column row
+func BuildMoveCursorSeq(column, row byte) []byte {
+	return []byte{CmdEscape, CmdEscMoveCursor, column, row}
+}
+
+// BuildBrightnessSeq creates the command sequence to set brightness.
+// Returns: ESC * level
+func BuildBrightnessSeq(level byte) []byte {
+	return []byte{CmdEscape, CmdEscBrightness, level}
+}
+
+// BuildCharsetSeq creates the command sequence to select a code table.
+// Returns: ESC t page
+func BuildCharsetSeq(page byte) []byte {
+	return []byte{CmdEscape, CmdEscCharsetTable, page}
+}
+
+// BuildMessageSeq creates a line message command carrying text.
+// Returns: ESC Q selector text CR
+func BuildMessageSeq(selector byte, text []byte) []byte {
+	seq := make([]byte, 0, len(text)+4)
+	seq = append(seq, CmdEscape, CmdEscMessage, selector)
+	seq = append(seq, text...)
+	return append(seq, CmdCarriageReturn)
+}
@@ -0,0 +1,156 @@
+package declarative
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/corrreia/govfd/types"
+)
+
+const poleDisplayYAML = `
+name: TEST-POLE
+description: Test pole display
+commands:
+  clear: "1B 40"
+  move_cursor: "1F 24 {col} {row}"
+  brightness: "1F 58 {level}"
+  blink: "1F 45 {ms}"
+  reverse_on: "1F 72 01"
+  reverse_off: "1F 72 00"
+parameters:
+  col: {min: 1, max: 20, offset: -1}
+  row: {min: 1, max: 2, offset: -1}
+  level: {min: 1, max: 8}
+models:
+  - id: TEST_POLE_20X2
+    name: Test Pole 20x2
+    columns: 20
+    rows: 2
+    baud_rate: 19200
+`
+
+func mustParse(t *testing.T, text string) *DeclarativeProtocol {
+	t.Helper()
+	def, err := Parse([]byte(text))
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	p, err := New(def)
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	return p
+}
+
+func TestTemplatesWithOffsets(t *testing.T) {
+	p := mustParse(t, poleDisplayYAML)
+	tests := []struct {
+		name string
+		cmd  func() ([]byte, error)
+		want string
+	}{
+		{"clear", p.Clear, "\x1b\x40"},
+		{"move cursor", func() ([]byte, error) { return p.MoveCursor(20, 2) }, "\x1f\x24\x13\x01"},
+		{"brightness", func() ([]byte, error) { return p.SetBrightness(8) }, "\x1f\x58\x08"},
+		{"blink", func() ([]byte, error) { return p.SetBlink(500) }, "\x1f\x45\x0a"},
+		{"reverse", func() ([]byte, error) { return p.SetReverse(true) }, "\x1f\x72\x01"},
+	}
+	for _, tt := range tests {
+		got, err := tt.cmd()
+		if err != nil {
+			t.Errorf("%s error: %v", tt.name, err)
+			continue
+		}
+		if string(got) != tt.want {
+			t.Errorf("%s = % X, want % X", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParameterRanges(t *testing.T) {
+	p := mustParse(t, poleDisplayYAML)
+	if _, err := p.MoveCursor(21, 1); err == nil {
+		t.Error("MoveCursor(21, 1) succeeded beyond max 20")
+	}
+	if _, err := p.MoveCursor(0, 1); err == nil {
+		t.Error("MoveCursor(0, 1) succeeded below min 1")
+	}
+	if _, err := p.SetBrightness(9); err == nil {
+		t.Error("SetBrightness(9) succeeded beyond max 8")
+	}
+}
+
+func TestMissingCommandsUnsupported(t *testing.T) {
+	p := mustParse(t, poleDisplayYAML)
+	if _, err := p.SelfTest(); !errors.Is(err, types.ErrUnsupported) {
+		t.Errorf("SelfTest error = %v, want ErrUnsupported", err)
+	}
+	if _, err := p.SetClock(12, 0); !errors.Is(err, types.ErrUnsupported) {
+		t.Errorf("SetClock error = %v, want ErrUnsupported", err)
+	}
+}
+
+func TestJSONDefinition(t *testing.T) {
+	p := mustParse(t, `{
+		"name": "TEST-JSON",
+		"commands": {"clear": "0C", "move_cursor": "1B 5B {row} 3B {col} 48"},
+		"parameters": {"row": {"format": "ascii"}, "col": {"format": "ascii"}}
+	}`)
+	got, err := p.MoveCursor(12, 2)
+	if err != nil {
+		t.Fatalf("MoveCursor error: %v", err)
+	}
+	if string(got) != "\x1b[2;12H" {
+		t.Errorf("MoveCursor = %q, want %q", got, "\x1b[2;12H")
+	}
+}
+
+func TestInvalidDefinitions(t *testing.T) {
+	tests := map[string]string{
+		"no name":             `commands: {clear: "0C"}`,
+		"no commands":         `name: X`,
+		"unknown command":     "name: X\ncommands: {explode: \"00\"}",
+		"unknown field":       "name: X\ncolour: red\ncommands: {clear: \"0C\"}",
+		"bad hex":             "name: X\ncommands: {clear: \"1G\"}",
+		"wrong placeholder":   "name: X\ncommands: {brightness: \"1F 58 {col}\"}",
+		"broken placeholder":  "name: X\ncommands: {brightness: \"1F 58 {level\"}",
+		"unknown parameter":   "name: X\ncommands: {clear: \"0C\"}\nparameters: {speed: {max: 3}}",
+		"inverted range":      "name: X\ncommands: {clear: \"0C\"}\nparameters: {level: {min: 5, max: 2}}",
+		"unknown format":      "name: X\ncommands: {clear: \"0C\"}\nparameters: {level: {format: bcd}}",
+		"model without size":  "name: X\ncommands: {clear: \"0C\"}\nmodels: [{id: M}]",
+		"pages without table": "name: X\ncommands: {clear: \"0C\"}\nmodels: [{id: M, columns: 20, rows: 2, code_pages: {PC437: 0, PC850: 2}}]",
+		"duplicate model":     "name: X\ncommands: {clear: \"0C\"}\nmodels: [{id: M, columns: 20, rows: 2}, {id: M, columns: 40, rows: 2}]",
+	}
+	for name, text := range tests {
+		def, err := Parse([]byte(text))
+		if err == nil {
+			_, err = New(def)
+		}
+		if err == nil {
+			t.Errorf("%s: definition accepted", name)
+		}
+	}
+}
+
+func TestOffsetOutsideByte(t *testing.T) {
+	p := mustParse(t, "name: X\ncommands: {move_cursor: \"10 {col} {row}\"}\nparameters: {col: {min: 0, offset: -1}}")
+	if _, err := p.MoveCursor(0, 1); err == nil || !strings.Contains(err.Error(), "outside a byte") {
+		t.Errorf("MoveCursor(0, 1) error = %v, want an out-of-byte error", err)
+	}
+}
+
+func TestModelProfile(t *testing.T) {
+	p := mustParse(t, poleDisplayYAML)
+	def := p.Definition()
+	profile := def.Models[0].Profile(def)
+	if profile.CommandProtocol != "TEST-POLE" || profile.Columns != 20 || profile.Rows != 2 {
+		t.Errorf("profile = %+v", profile)
+	}
+	if profile.DefaultBaudRate != 19200 || profile.BrightnessLevels != 8 || !profile.SupportsCursorBlink {
+		t.Errorf("profile settings = %+v", profile)
+	}
+	if profile.SupportsCharsetTable || len(profile.CodePages) != 1 {
+		t.Errorf("code pages = %v without a charset command, want PC437 only", profile.CodePages)
+	}
+}
@@ -0,0 +1,252 @@
+// Package declarative implements command protocols described by data
+// instead of Go code. Many pole displays differ from one another only in
+// their command bytes; a Definition, loaded from JSON or YAML, lists those
+// bytes as templates such as "1F 24 {col} {row}".
+package declarative
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/corrreia/govfd/types"
+
+	"go.bug.st/serial"
+	"gopkg.in/yaml.v3"
+)
+
+// Definition describes a command protocol and, optionally, the display
+// models that use it.
+//
+//	name: ACME-PD
+//	description: ACME pole display
+//	commands:
+//	  clear: "1B 40"
+//	  move_cursor: "1F 24 {col} {row}"
+//	  brightness: "1F 58 {level}"
+//	parameters:
+//	  level: {min: 1, max: 4}
+//	models:
+//	  - id: ACME_PD2000
+//	    columns: 20
+//	    rows: 2
+type Definition struct {
+	Name        string               `yaml:"name"`
+	Description string               `yaml:"description"`
+	Commands    map[string]string    `yaml:"commands"`   // Byte templates by command name
+	Parameters  map[string]Parameter `yaml:"parameters"` // Overrides of the parameter defaults
+	Models      []ModelDefinition    `yaml:"models"`
+}
+
+// Parameter describes how a placeholder value becomes bytes. The value is
+// checked against Min..Max, divided by Divisor, then Offset is added.
+type Parameter struct {
+	Min     *int   `yaml:"min"`
+	Max     *int   `yaml:"max"`
+	Offset  int    `yaml:"offset"`  // e.g. -1 for a display counting from 0
+	Divisor int    `yaml:"divisor"` // e.g. 50 for a blink period in 50 ms steps
+	Format  string `yaml:"format"`  // "byte" (default) or "ascii" decimal digits
+}
+
+// ModelDefinition describes a display model using the protocol.
+type ModelDefinition struct {
+	ID           types.Model         `yaml:"id"`
+	Name         string              `yaml:"name"`
+	Manufacturer string              `yaml:"manufacturer"`
+	Columns      int                 `yaml:"columns"`
+	Rows         int                 `yaml:"rows"`
+	BaudRate     int                 `yaml:"baud_rate"` // 9600 if unset
+	CodePages    types.CodePageTable `yaml:"code_pages"`
+}
+
+// Commands and the placeholders each one takes.
+var commandParameters = map[string][]string{
+	CommandClear:                {},
+	CommandFormFeed:             {},
+	CommandMoveCursor:           {"col", "row"},
+	CommandBrightness:           {"level"},
+	CommandBlink:                {"ms"},
+	CommandCharset:              {"page"},
+	CommandSelfTest:             {},
+	CommandReverseOn:            {},
+	CommandReverseOff:           {},
+	CommandOverwriteMode:        {},
+	CommandVerticalScrollMode:   {},
+	CommandHorizontalScrollMode: {},
+	CommandSetClock:             {"hour", "minute"},
+	CommandShowClock:            {},
+}
+
+// Command names used as keys of Definition.Commands
+const (
+	CommandClear                = "clear"
+	CommandFormFeed             = "form_feed"
+	CommandMoveCursor           = "move_cursor"
+	CommandBrightness           = "brightness"
+	CommandBlink                = "blink"
+	CommandCharset              = "charset"
+	CommandSelfTest             = "self_test"
+	CommandReverseOn            = "reverse_on"
+	CommandReverseOff           = "reverse_off"
+	CommandOverwriteMode        = "overwrite_mode"
+	CommandVerticalScrollMode   = "vertical_scroll_mode"
+	CommandHorizontalScrollMode = "horizontal_scroll_mode"
+	CommandSetClock             = "set_clock"
+	CommandShowClock            = "show_clock"
+)
+
+// defaultParameters are the ranges of the placeholders, in the units of the
+// corresponding Display methods, before a definition overrides them.
+var defaultParameters = map[string]param{
+	"col":    {min: 1, max: 255, divisor: 1},
+	"row":    {min: 1, max: 255, divisor: 1},
+	"level":  {min: 1, max: 4, divisor: 1},
+	"ms":     {min: 0, max: 255 * 50, divisor: 50},
+	"page":   {min: 0, max: 255, divisor: 1},
+	"hour":   {min: 0, max: 23, divisor: 1},
+	"minute": {min: 0, max: 59, divisor: 1},
+}
+
+// Parse reads a definition from YAML or JSON (which YAML includes). Unknown
+// fields are rejected so typos do not go unnoticed.
+func Parse(data []byte) (*Definition, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	var def Definition
+	if err := decoder.Decode(&def); err != nil {
+		return nil, fmt.Errorf("parse protocol definition: %w", err)
+	}
+	return &def, nil
+}
+
+// LoadFile reads a definition from a .yaml, .yml or .json file.
+func LoadFile(path string) (*Definition, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	def, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return def, nil
+}
+
+// Profile returns the model profile of m for the protocol def describes.
+// A display whose protocol cannot select code tables is assumed to show
+// PC437 unless the model lists its table.
+func (m ModelDefinition) Profile(def *Definition) types.ModelProfile {
+	baudRate := m.BaudRate
+	if baudRate == 0 {
+		baudRate = 9600
+	}
+	name := m.Name
+	if name == "" {
+		name = string(m.ID)
+	}
+	_, hasCharset := def.Commands[CommandCharset]
+	codePages := m.CodePages
+	if codePages == nil && !hasCharset {
+		codePages = types.CodePageTable{types.CodePagePC437: 0}
+	}
+	profile := types.ModelProfile{
+		Name:                 name,
+		Manufacturer:         m.Manufacturer,
+		Model:                string(m.ID),
+		Columns:              m.Columns,
+		Rows:                 m.Rows,
+		DefaultBaudRate:      baudRate,
+		DefaultDataBits:      8,
+		DefaultParity:        serial.NoParity,
+		DefaultStopBits:      serial.OneStopBit,
+		CommandProtocol:      def.Name,
+		SupportsCursorBlink:  def.Commands[CommandBlink] != "",
+		SupportsCharsetTable: hasCharset,
+		SupportsSelfTest:     def.Commands[CommandSelfTest] != "",
+		CodePages:            codePages,
+	}
+	if def.Commands[CommandBrightness] != "" {
+		profile.SupportsBrightness = true
+		profile.BrightnessLevels = def.parameter("level").max
+	}
+	return profile
+}
+
+// Validate checks the definition: known commands with well-formed templates
+// using only their own placeholders, sensible parameters and complete models.
+func (def *Definition) Validate() error {
+	if def.Name == "" {
+		return errors.New("protocol name is required")
+	}
+	if len(def.Commands) == 0 {
+		return fmt.Errorf("protocol %s: no commands defined", def.Name)
+	}
+	for _, name := range sortedKeys(def.Commands) {
+		allowed, known := commandParameters[name]
+		if !known {
+			return fmt.Errorf("protocol %s: unknown command %q", def.Name, name)
+		}
+		if _, err := parseTemplate(def.Commands[name], allowed); err != nil {
+			return fmt.Errorf("protocol %s: command %s: %w", def.Name, name, err)
+		}
+	}
+	for _, name := range sortedKeys(def.Parameters) {
+		if _, known := defaultParameters[name]; !known {
+			return fmt.Errorf("protocol %s: unknown parameter %q", def.Name, name)
+		}
+		if err := def.parameter(name).validate(); err != nil {
+			return fmt.Errorf("protocol %s: parameter %s: %w", def.Name, name, err)
+		}
+	}
+	_, hasCharset := def.Commands[CommandCharset]
+	seen := make(map[types.Model]bool, len(def.Models))
+	for _, m := range def.Models {
+		if m.ID == "" {
+			return fmt.Errorf("protocol %s: model id is required", def.Name)
+		}
+		if seen[m.ID] {
+			return fmt.Errorf("protocol %s: duplicate model %s", def.Name, m.ID)
+		}
+		seen[m.ID] = true
+		if m.Columns < 1 || m.Rows < 1 {
+			return fmt.Errorf("protocol %s: model %s: columns and rows must be >= 1", def.Name, m.ID)
+		}
+		if !hasCharset && len(m.CodePages) > 1 {
+			return fmt.Errorf("protocol %s: model %s: several code pages but no charset command", def.Name, m.ID)
+		}
+	}
+	return nil
+}
+
+// parameter returns the effective settings of the placeholder name.
+func (def *Definition) parameter(name string) param {
+	p := defaultParameters[name]
+	override, ok := def.Parameters[name]
+	if !ok {
+		return p
+	}
+	if override.Min != nil {
+		p.min = *override.Min
+	}
+	if override.Max != nil {
+		p.max = *override.Max
+	}
+	if override.Divisor != 0 {
+		p.divisor = override.Divisor
+	}
+	p.offset = override.Offset
+	p.format = override.Format
+	return p
+}
+
+// sortedKeys returns the keys of m in order, so errors are deterministic.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,135 @@
+package declarative
+
+import (
+	"fmt"
+
+	"github.com/corrreia/govfd/types"
+)
+
+// DeclarativeProtocol implements the Protocol interface from a Definition.
+// Commands the definition leaves out return types.ErrUnsupported.
+type DeclarativeProtocol struct {
+	def       *Definition
+	templates map[string]template
+	params    map[string]param
+}
+
+// New validates def and builds the protocol it describes.
+func New(def *Definition) (*DeclarativeProtocol, error) {
+	if err := def.Validate(); err != nil {
+		return nil, err
+	}
+	p := &DeclarativeProtocol{
+		def:       def,
+		templates: make(map[string]template, len(def.Commands)),
+		params:    make(map[string]param, len(defaultParameters)),
+	}
+	for name, text := range def.Commands {
+		p.templates[name], _ = parseTemplate(text, commandParameters[name])
+	}
+	for name := range defaultParameters {
+		p.params[name] = def.parameter(name)
+	}
+	return p, nil
+}
+
+// Definition returns the definition the protocol was built from.
+func (p *DeclarativeProtocol) Definition() *Definition {
+	return p.def
+}
+
+// build renders the template of command with the given placeholder values.
+func (p *DeclarativeProtocol) build(command string, values map[string]int) ([]byte, error) {
+	t, ok := p.templates[command]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", command, types.ErrUnsupported)
+	}
+	out := make([]byte, 0, len(t))
+	for _, seg := range t {
+		if seg.param == "" {
+			out = append(out, seg.literal)
+			continue
+		}
+		var err error
+		if out, err = p.params[seg.param].render(out, seg.param, values[seg.param]); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// GetName returns the protocol name.
+func (p *DeclarativeProtocol) GetName() string {
+	return p.def.Name
+}
+
+// GetDescription returns the protocol description.
+func (p *DeclarativeProtocol) GetDescription() string {
+	return p.def.Description
+}
+
+// Clear returns the command sequence to initialize/clear the display.
+func (p *DeclarativeProtocol) Clear() ([]byte, error) {
+	return p.build(CommandClear, nil)
+}
+
+// FormFeed returns the command sequence to clear screen content.
+func (p *DeclarativeProtocol) FormFeed() ([]byte, error) {
+	return p.build(CommandFormFeed, nil)
+}
+
+// MoveCursor returns the command sequence to move cursor to position (1-based).
+func (p *DeclarativeProtocol) MoveCursor(column, row int) ([]byte, error) {
+	return p.build(CommandMoveCursor, map[string]int{"col": column, "row": row})
+}
+
+// SetBrightness returns the command sequence to set brightness level.
+func (p *DeclarativeProtocol) SetBrightness(level int) ([]byte, error) {
+	return p.build(CommandBrightness, map[string]int{"level": level})
+}
+
+// SetBlink returns the command sequence to set cursor blink period.
+func (p *DeclarativeProtocol) SetBlink(intervalMs int) ([]byte, error) {
+	return p.build(CommandBlink, map[string]int{"ms": intervalMs})
+}
+
+// SetCharset returns the command sequence to set character encoding table.
+func (p *DeclarativeProtocol) SetCharset(page int) ([]byte, error) {
+	return p.build(CommandCharset, map[string]int{"page": page})
+}
+
+// SelfTest returns the command sequence to execute self-test.
+func (p *DeclarativeProtocol) SelfTest() ([]byte, error) {
+	return p.build(CommandSelfTest, nil)
+}
+
+// SetReverse returns the command sequence to turn reverse characters on or off.
+func (p *DeclarativeProtocol) SetReverse(enabled bool) ([]byte, error) {
+	if enabled {
+		return p.build(CommandReverseOn, nil)
+	}
+	return p.build(CommandReverseOff, nil)
+}
+
+// SetDisplayMode returns the command sequence to select a display mode.
+func (p *DeclarativeProtocol) SetDisplayMode(mode types.DisplayMode) ([]byte, error) {
+	switch mode {
+	case types.DisplayModeOverwrite:
+		return p.build(CommandOverwriteMode, nil)
+	case types.DisplayModeVerticalScroll:
+		return p.build(CommandVerticalScrollMode, nil)
+	case types.DisplayModeHorizontalScroll:
+		return p.build(CommandHorizontalScrollMode, nil)
+	}
+	return nil, fmt.Errorf("unknown display mode %q", mode)
+}
+
+// SetClock returns the command sequence to set the built-in clock and show it.
+func (p *DeclarativeProtocol) SetClock(hour, minute int) ([]byte, error) {
+	return p.build(CommandSetClock, map[string]int{"hour": hour, "minute": minute})
+}
+
+// ShowClock returns the command sequence to show the built-in clock.
+func (p *DeclarativeProtocol) ShowClock() ([]byte, error) {
+	return p.build(CommandShowClock, nil)
+}
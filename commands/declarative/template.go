@@ -0,0 +1,91 @@
+package declarative
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// segment is one element of a template: a literal byte or a placeholder.
+type segment struct {
+	literal byte
+	param   string // placeholder name, "" for a literal
+}
+
+// template is a parsed byte template such as "1F 24 {col} {row}".
+type template []segment
+
+// parseTemplate parses space-separated hex bytes (optionally 0x-prefixed)
+// and {name} placeholders, allowing only the given placeholder names.
+func parseTemplate(text string, allowed []string) (template, error) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return nil, errors.New("empty template")
+	}
+	t := make(template, 0, len(fields))
+	for _, field := range fields {
+		if name, ok := strings.CutPrefix(field, "{"); ok {
+			name, ok = strings.CutSuffix(name, "}")
+			if !ok || name == "" {
+				return nil, fmt.Errorf("malformed placeholder %q", field)
+			}
+			if !slices.Contains(allowed, name) {
+				return nil, fmt.Errorf("placeholder {%s} not allowed here", name)
+			}
+			t = append(t, segment{param: name})
+			continue
+		}
+		hex := strings.TrimPrefix(strings.TrimPrefix(field, "0x"), "0X")
+		if len(hex) != 2 {
+			return nil, fmt.Errorf("byte %q must be two hex digits", field)
+		}
+		b, err := strconv.ParseUint(hex, 16, 8)
+		if err != nil {
+			return nil, fmt.Errorf("byte %q is not hex", field)
+		}
+		t = append(t, segment{literal: byte(b)})
+	}
+	return t, nil
+}
+
+// param holds the effective settings of a placeholder.
+type param struct {
+	min, max int
+	offset   int
+	divisor  int
+	format   string
+}
+
+// validate checks settings given by a definition.
+func (p param) validate() error {
+	if p.min > p.max {
+		return fmt.Errorf("min %d exceeds max %d", p.min, p.max)
+	}
+	if p.divisor < 1 {
+		return errors.New("divisor must be >= 1")
+	}
+	if p.format != "" && p.format != "byte" && p.format != "ascii" {
+		return fmt.Errorf("unknown format %q", p.format)
+	}
+	return nil
+}
+
+// render appends the encoding of value to dst.
+func (p param) render(dst []byte, name string, value int) ([]byte, error) {
+	if value < p.min || value > p.max {
+		return nil, fmt.Errorf("%s must be between %d and %d", name, p.min, p.max)
+	}
+	n := value/p.divisor + p.offset
+	if p.format == "ascii" {
+		if n < 0 {
+			return nil, fmt.Errorf("%s %d encodes to negative %d", name, value, n)
+		}
+		return strconv.AppendInt(dst, int64(n), 10), nil
+	}
+	if n < 0 || n > 255 {
+		return nil, fmt.Errorf("%s %d encodes to %d, outside a byte", name, value, n)
+	}
+	return append(dst, byte(n)), nil
+}
@@ -0,0 +1,58 @@
+// Package dsp800 implements the ESC Q line command protocol of DSP800
+// pole displays.
+package dsp800
+
+import (
+	"github.com/corrreia/govfd/commands/internal/shadow"
+	"github.com/corrreia/govfd/types"
+)
+
+// DSP800Protocol implements the Protocol interface for DSP800 displays. The
+// protocol only replaces whole lines, so the embedded shadow.LineProtocol
+// emulates cursor addressing. Each display gets its own instance from
+// ForModel; the zero value emulates a 20x2 screen.
+type DSP800Protocol struct {
+	shadow.LineProtocol[lines]
+}
+
+// lines builds DSP800 line commands.
+type lines struct{}
+
+// Line builds the command showing text on a 1-based row.
+func (lines) Line(row int, text []byte) []byte {
+	if row == 1 {
+		return BuildLineSeq(LineUpper, text)
+	}
+	return BuildLineSeq(LineLower, text)
+}
+
+// ForModel returns a protocol with its own shadow screen of the model's size.
+func (p *DSP800Protocol) ForModel(profile *types.ModelProfile) (types.Protocol, error) {
+	sized, err := p.Sized(profile)
+	if err != nil {
+		return nil, err
+	}
+	return &DSP800Protocol{LineProtocol: sized}, nil
+}
+
+// GetName returns the protocol name.
+func (p *DSP800Protocol) GetName() string {
+	return types.ProtocolDSP800
+}
+
+// GetDescription returns the protocol description.
+func (p *DSP800Protocol) GetDescription() string {
+	return "DSP800 ESC Q line commands, with emulated cursor addressing"
+}
+
+// Clear returns the command sequence to initialize the display.
+func (p *DSP800Protocol) Clear() ([]byte, error) {
+	p.ResetScreen()
+	return SeqInitialize, nil
+}
+
+// FormFeed returns the command sequence to clear screen content.
+func (p *DSP800Protocol) FormFeed() ([]byte, error) {
+	p.ResetScreen()
+	return SeqClearScreen, nil
+}
@@ -0,0 +1,52 @@
+package dsp800
+
+// DSP800 command byte constants. DSP800 displays take whole lines of text
+// with ESC Q commands; there is no cursor addressing.
+
+// ASCII Control Characters
+const (
+	// Form Feed - clears the screen
+	CmdClearScreen = 0x0C
+
+	// Carriage Return - terminates line commands
+	CmdCarriageReturn = 0x0D
+
+	// Escape character - used as prefix for commands
+	CmdEscape = 0x1B // ESC
+)
+
+// Escape Sequence Commands (ESC + command)
+const (
+	// ESC @ - Initialize display
+	CmdEscInitialize = 0x40 // @
+
+	// ESC Q - Line commands (followed by A or B)
+	CmdEscLine = 0x51 // Q
+)
+
+// Line selectors (ESC Q + selector)
+const (
+	// A - Upper line
+	LineUpper = 0x41 // A
+
+	// B - Lower line
+	LineLower = 0x42 // B
+)
+
+// Complete Command Sequences as byte arrays for convenience
+var (
+	// Initialize display: ESC @
+	SeqInitialize = []byte{CmdEscape, CmdEscInitialize}
+
+	// Clear screen: FF
+	SeqClearScreen = []byte{CmdClearScreen}
+)
+
+// BuildLineSeq creates the command sequence showing text on the selected line.
+// Returns: ESC Q selector text CR
+func BuildLineSeq(selector byte, text []byte) []byte {
+	seq := make([]byte, 0, len(text)+4)
+	seq = append(seq, CmdEscape, CmdEscLine, selector)
+	seq = append(seq, text...)
+	return append(seq, CmdCarriageReturn)
+}
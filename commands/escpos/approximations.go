@@ -0,0 +1,108 @@
+package escpos
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// builtinApproximations lists ASCII stand-ins for symbols common in UI text.
+// They are used only for runes no available code table, international set or
+// glyph can show, so e.g. '°' and the light box-drawing characters stay real
+// on PC437, and '₩' stays real where the Korea set can be selected.
+var builtinApproximations = map[rune]string{
+	// Marks
+	'✓': "v", '✔': "v", '✗': "x", '✘': "x", '•': "*", '·': ".", '…': "...",
+	'©': "(c)", '®': "(R)", '™': "TM", '°': "o", '§': "S", '¶': "P",
+
+	// Arrows
+	'→': "->", '←': "<-", '↑': "^", '↓': "v", '↔': "<->", '⇒': "=>", '⇐': "<=",
+	'▶': ">", '◀': "<", '▲': "^", '▼': "v",
+
+	// Math and fractions
+	'±': "+/-", '×': "x", '÷': "/", '≤': "<=", '≥': ">=", '≠': "!=", '≈': "~",
+	'½': "1/2", '¼': "1/4", '¾': "3/4", '²': "2", '³': "3", 'µ': "u",
+
+	// Punctuation
+	'‘': "'", '’': "'", '‚': ",", '“': "\"", '”': "\"", '„': "\"",
+	'–': "-", '—': "-", '‐': "-", '−': "-", '«': "<<", '»': ">>",
+	'\u00A0': " ", '\u2009': " ", '\u202F': " ",
+
+	// Currency
+	'€': "EUR", '£': "GBP", '¥': "JPY", '₩': "KRW", '¢': "c",
+
+	// Box drawing: lines, corners and junctions
+	'─': "-", '━': "-", '═': "=", '│': "|", '┃': "|", '║': "|",
+	'┌': "+", '┐': "+", '└': "+", '┘': "+", '├': "+", '┤': "+", '┬': "+", '┴': "+", '┼': "+",
+	'┏': "+", '┓': "+", '┗': "+", '┛': "+", '┣': "+", '┫': "+", '┳': "+", '┻': "+", '╋': "+",
+	'╔': "+", '╗': "+", '╚': "+", '╝': "+", '╠': "+", '╣': "+", '╦': "+", '╩': "+", '╬': "+",
+	'╭': "+", '╮': "+", '╯': "+", '╰': "+",
+	'░': "#", '▒': "#", '▓': "#", '█': "#",
+}
+
+// SetApproximation sets the stand-in shown for r when no available code table
+// or user-defined glyph has it, overriding any built-in approximation.
+func (e *CharsetEncoder) SetApproximation(r rune, replacement string) error {
+	if replacement == "" {
+		return errors.New("approximation must not be empty")
+	}
+	if strings.ContainsFunc(replacement, unicode.IsControl) || !utf8.ValidString(replacement) {
+		return errors.New("approximation must be printable UTF-8")
+	}
+	if e.approximations == nil {
+		e.approximations = make(map[rune]string)
+	}
+	e.approximations[r] = replacement
+	return nil
+}
+
+// Approximation returns the stand-in for r, if there is one.
+func (e *CharsetEncoder) Approximation(r rune) (string, bool) {
+	if replacement, ok := e.approximations[r]; ok {
+		return replacement, true
+	}
+	replacement, ok := builtinApproximations[r]
+	return replacement, ok
+}
+
+// fold rewrites text into runes the display can show: full-width Katakana
+// becomes half-width, and symbols that neither a code table nor a glyph can
+// show become their approximation.
+func (e *CharsetEncoder) fold(text string) string {
+	i := strings.IndexFunc(text, func(r rune) bool {
+		_, ok := e.foldRune(r)
+		return ok
+	})
+	if i < 0 {
+		return text
+	}
+	var b strings.Builder
+	b.Grow(len(text))
+	b.WriteString(text[:i])
+	for _, r := range text[i:] {
+		if folded, ok := e.foldRune(r); ok {
+			b.WriteString(folded)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// foldRune returns what fold rewrites r to, if anything.
+func (e *CharsetEncoder) foldRune(r rune) (string, bool) {
+	if r < utf8.RuneSelf {
+		return "", false
+	}
+	if folded, ok := foldKatakanaRune(r); ok {
+		return folded, true
+	}
+	if e.anyCodePageHas(r) || e.anyInternationalHas(r) {
+		return "", false
+	}
+	if _, ok := e.glyphs.glyphs[r]; ok {
+		return "", false
+	}
+	return e.Approximation(r)
+}
@@ -0,0 +1,131 @@
+package escpos
+
+import (
+	"testing"
+
+	"github.com/corrreia/govfd/types"
+)
+
+func TestApproximationsOnlyWhenNoCodePageHasRune(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"20°C", "20\xF8C"},   // PC437 has '°'
+		{"±½", "\xF1\xAB"},    // and '±', '½'
+		{"Next →", "Next ->"}, // no table has '→'
+		{"ACME™", "ACMETM"},
+		{"✓ ok", "v ok"},
+	}
+	for _, tt := range tests {
+		enc := NewCharsetEncoder()
+		mock := newMockDisplay()
+		got, err := enc.EncodeTextWithAutoCharsetSwitching(tt.input, mock)
+		if err != nil {
+			t.Fatalf("encode %q: %v", tt.input, err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("encode %q = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestApproximationPrefersRealGlyphInOtherPage(t *testing.T) {
+	enc := NewCharsetEncoder()
+	mock := newMockDisplay()
+
+	// '©' is in PC850, so the encoder switches rather than approximating.
+	got, err := enc.EncodeTextWithAutoCharsetSwitching("©", mock)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if mock.currentPage != chartablePC850 || string(got) != "\xB8" {
+		t.Errorf("got %q on page %d, want \"\\xB8\" on PC850", got, mock.currentPage)
+	}
+
+	// Without PC850 the approximation is used.
+	enc = NewCharsetEncoder()
+	if err := enc.SetCodePages(types.CodePageTable{types.CodePagePC437: 0}); err != nil {
+		t.Fatalf("SetCodePages: %v", err)
+	}
+	mock = newMockDisplay()
+	got, err = enc.EncodeTextWithAutoCharsetSwitching("©", mock)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if string(got) != "(c)" || mock.switchCount != 0 {
+		t.Errorf("got %q after %d switches, want \"(c)\" with none", got, mock.switchCount)
+	}
+}
+
+func TestUserApproximations(t *testing.T) {
+	enc := NewCharsetEncoder()
+	if err := enc.SetApproximation('→', ">"); err != nil {
+		t.Fatalf("SetApproximation: %v", err)
+	}
+	if err := enc.SetApproximation('☕', "[cafe]"); err != nil {
+		t.Fatalf("SetApproximation: %v", err)
+	}
+	if err := enc.SetApproximation('x', ""); err == nil {
+		t.Error("expected error for empty approximation")
+	}
+	if err := enc.SetApproximation('x', "a\nb"); err == nil {
+		t.Error("expected error for approximation with control characters")
+	}
+	mock := newMockDisplay()
+	got, err := enc.EncodeTextWithAutoCharsetSwitching("☕ → 1", mock)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if string(got) != "[cafe] > 1" {
+		t.Errorf("got %q, want %q", got, "[cafe] > 1")
+	}
+}
+
+func TestGlyphPreferredOverApproximation(t *testing.T) {
+	enc := NewCharsetEncoder()
+	check := types.Glyph{0, 1, 2, 0x14, 8, 0, 0}
+	if _, _, err := enc.DefineGlyph('✓', check); err != nil {
+		t.Fatalf("DefineGlyph: %v", err)
+	}
+	mock := newMockDisplay()
+	got, err := enc.EncodeTextWithAutoCharsetSwitching("✓", mock)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if len(mock.downloads) != 1 || len(got) != 1 || got[0] != mock.downloads[0] {
+		t.Errorf("got %q with downloads % X, want the glyph slot code", got, mock.downloads)
+	}
+}
+
+func TestApproximationReported(t *testing.T) {
+	enc := NewCharsetEncoder()
+	mock := newMockDisplay()
+	got, report, err := enc.EncodeTextWithReport("a→b", mock)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if report.Cells != len(got) || report.Cells != 4 {
+		t.Errorf("cells = %d, want 4", report.Cells)
+	}
+	want := types.Substitution{Offset: 1, Rune: '→', Replacement: "->"}
+	if len(report.Substitutions) != 1 || report.Substitutions[0] != want {
+		t.Errorf("substitutions = %+v, want [%+v]", report.Substitutions, want)
+	}
+}
+
+func TestDoubleByteUsesApproximations(t *testing.T) {
+	enc := NewCharsetEncoder()
+	if err := enc.SetDoubleByteCharset(types.DoubleByteShiftJIS); err != nil {
+		t.Fatalf("SetDoubleByteCharset: %v", err)
+	}
+	mock := newMockDisplay()
+	// Shift_JIS lacks '™'.
+	got, err := enc.EncodeTextWithAutoCharsetSwitching("寿司™", mock)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if len(got) != 6 || string(got[4:]) != "TM" {
+		t.Errorf("got % X, want 4 Kanji bytes then \"TM\"", got)
+	}
+}
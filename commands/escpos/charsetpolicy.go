@@ -0,0 +1,132 @@
+package escpos
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/corrreia/govfd/types"
+)
+
+// SetCharsetPolicy sets how the encoder selects code tables. A pinned table
+// must be one the model supports; preferred tables it lacks are skipped.
+func (e *CharsetEncoder) SetCharsetPolicy(policy types.CharsetPolicy) error {
+	if policy.Pinned != "" {
+		if _, ok := e.codePages[policy.Pinned]; !ok {
+			return fmt.Errorf("pinned code page %s not supported by model", policy.Pinned)
+		}
+	}
+	if policy.Locale != "" {
+		if types.LocaleCodePagesFor(policy.Locale) == nil {
+			return fmt.Errorf("no code page preferences for locale %q", policy.Locale)
+		}
+	}
+	policy.Preferred = slices.Clone(policy.Preferred)
+	e.policy = policy
+	return nil
+}
+
+// CharsetPolicy returns the encoder's code table selection policy.
+func (e *CharsetEncoder) CharsetPolicy() types.CharsetPolicy {
+	policy := e.policy
+	policy.Preferred = slices.Clone(policy.Preferred)
+	return policy
+}
+
+// Plan selects, ahead of time, a code table covering all of texts (e.g. every
+// line of the next screen) and switches the display to it, downloading any
+// glyphs they need. Writing the texts afterwards then needs no switch, since
+// the current table is kept while it covers the text.
+func (e *CharsetEncoder) Plan(texts []string, display types.CharsetSwitcher) error {
+	_, err := e.encode(strings.Join(texts, ""), display, nil)
+	return err
+}
+
+// pinned returns the pinned code table, if the policy pins a supported one.
+func (e *CharsetEncoder) pinned() (types.CodePage, bool) {
+	if e.policy.Pinned == "" {
+		return "", false
+	}
+	_, ok := e.codePages[e.policy.Pinned]
+	return e.policy.Pinned, ok
+}
+
+// keepsCurrent reports whether text should stay on the current table
+// codePage if it covers the text, rather than look for a better one.
+func (e *CharsetEncoder) keepsCurrent(codePage types.CodePage, text string) bool {
+	if pinned, ok := e.pinned(); ok && pinned != codePage {
+		return false
+	}
+	return !e.policy.SwitchToBest || isASCII(text)
+}
+
+// selectable returns the code tables the encoder may select: the pinned
+// table, or else every table the model supports.
+func (e *CharsetEncoder) selectable() pageSet {
+	if pinned, ok := e.pinned(); ok {
+		return tableFor(pinned).bit
+	}
+	return e.available
+}
+
+// candidates returns the supported code tables to try for text, best first:
+// the pinned table alone, or the preferred tables followed by those suggested
+// by the characters of text.
+func (e *CharsetEncoder) candidates(text string) pageList {
+	var list pageList
+	if pinned, ok := e.pinned(); ok {
+		list.add(pinned)
+		return list
+	}
+	preferred := e.policy.Preferred
+	if len(preferred) == 0 && e.policy.Locale != "" {
+		preferred = types.LocaleCodePagesFor(e.policy.Locale)
+	}
+	for _, codePage := range preferred {
+		if _, ok := e.codePages[codePage]; ok {
+			list.add(codePage)
+		}
+	}
+	for _, codePage := range candidateCodePages(text) {
+		if _, ok := e.codePages[codePage]; ok {
+			list.add(codePage)
+		}
+	}
+	return list
+}
+
+// fallbackCodePages returns every table to consider when none covers the
+// whole text: the current table first so it wins ties, then candidates, then
+// the other supported tables by page number. A pinned table stands alone.
+func (e *CharsetEncoder) fallbackCodePages(candidates pageList) pageList {
+	if _, ok := e.pinned(); ok {
+		return candidates
+	}
+	var list pageList
+	if current, ok := e.codePageFor(e.currentCharset); ok {
+		list.add(current)
+	}
+	for _, codePage := range candidates.slice() {
+		list.add(codePage)
+	}
+	for _, codePage := range e.byPage {
+		list.add(codePage)
+	}
+	return list
+}
+
+// encodePartial encodes text in codePage under the USA international set,
+// replacing runes the table lacks with '?'.
+func encodePartial(codePage types.CodePage, text string) []byte {
+	t := tableFor(codePage)
+	result := make([]byte, 0, utf8.RuneCountInString(text))
+	for _, r := range text {
+		b, ok := t.encodeRune(r)
+		if !ok {
+			b = '?'
+		}
+		result = append(result, b)
+	}
+	return result
+}
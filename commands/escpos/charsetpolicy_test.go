@@ -0,0 +1,177 @@
+package escpos
+
+import (
+	"testing"
+
+	"github.com/corrreia/govfd/types"
+)
+
+func TestPinnedCodePageNeverSwitchesAway(t *testing.T) {
+	enc := NewCharsetEncoder()
+	if err := enc.SetCharsetPolicy(types.CharsetPolicy{Pinned: types.CodePagePC850}); err != nil {
+		t.Fatalf("SetCharsetPolicy: %v", err)
+	}
+	mock := newMockDisplay()
+
+	// "ã" would normally select PC860; pinned PC850 has it too.
+	if _, err := enc.EncodeTextWithAutoCharsetSwitching("São", mock); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if mock.currentPage != chartablePC850 || mock.switchCount != 1 {
+		t.Fatalf("page = %d after %d switches, want PC850 after 1", mock.currentPage, mock.switchCount)
+	}
+	// "€" is not in PC850: it is approximated rather than switching to PC858.
+	encoded, err := enc.EncodeTextWithAutoCharsetSwitching("5€", mock)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if string(encoded) != "5EUR" || mock.switchCount != 1 {
+		t.Errorf("got %q after %d switches, want \"5EUR\" with no new switch", encoded, mock.switchCount)
+	}
+}
+
+func TestPinnedCodePageMustBeSupported(t *testing.T) {
+	enc := NewCharsetEncoder()
+	if err := enc.SetCodePages(types.CodePageTable{types.CodePagePC437: 0}); err != nil {
+		t.Fatalf("SetCodePages: %v", err)
+	}
+	if err := enc.SetCharsetPolicy(types.CharsetPolicy{Pinned: types.CodePagePC858}); err == nil {
+		t.Error("expected error pinning a code page the model lacks")
+	}
+	if err := enc.SetCharsetPolicy(types.CharsetPolicy{Locale: "xx"}); err == nil {
+		t.Error("expected error for a locale without preferences")
+	}
+}
+
+func TestPreferredCodePagesTriedFirst(t *testing.T) {
+	enc := NewCharsetEncoder()
+	if err := enc.SetCharsetPolicy(types.CharsetPolicy{Locale: "pt-BR"}); err != nil {
+		t.Fatalf("SetCharsetPolicy: %v", err)
+	}
+	mock := newMockDisplay()
+
+	// "Ê" alone would pick PC850; the Portuguese preference puts PC860 first.
+	if _, err := enc.EncodeTextWithAutoCharsetSwitching("Ê", mock); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if mock.currentPage != chartablePC860 {
+		t.Errorf("page = %d, want PC860 (%d)", mock.currentPage, chartablePC860)
+	}
+}
+
+func TestRegisteredLocaleCodePages(t *testing.T) {
+	pages := []types.CodePage{types.CodePagePC858, types.CodePagePC437}
+	if err := types.RegisterLocaleCodePages("nl", pages); err != nil {
+		t.Fatalf("RegisterLocaleCodePages: %v", err)
+	}
+	pages[0] = types.CodePagePC860 // The registry keeps its own copy
+	types.LocaleCodePagesFor("nl-BE")[0] = types.CodePagePC860
+
+	if got := types.LocaleCodePagesFor("NL-be"); len(got) != 2 || got[0] != types.CodePagePC858 {
+		t.Errorf("LocaleCodePagesFor(NL-be) = %v, want [PC858 PC437]", got)
+	}
+	for _, language := range []string{"nl", "pt", "", "nl-NL"} {
+		if err := types.RegisterLocaleCodePages(language, pages); err == nil {
+			t.Errorf("RegisterLocaleCodePages(%q) succeeded", language)
+		}
+	}
+
+	enc := NewCharsetEncoder()
+	if err := enc.SetCharsetPolicy(types.CharsetPolicy{Locale: "nl-NL"}); err != nil {
+		t.Fatalf("SetCharsetPolicy: %v", err)
+	}
+	mock := newMockDisplay()
+	if _, err := enc.EncodeTextWithAutoCharsetSwitching("Ê", mock); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	// "Ê" alone would pick PC850; the Dutch preference puts PC858 first.
+	if mock.currentPage != chartablePC858 {
+		t.Errorf("page = %d, want PC858 (%d)", mock.currentPage, chartablePC858)
+	}
+}
+
+func TestCurrentCodePageKeptWhenItCovers(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		policy   types.CharsetPolicy
+		wantPage int
+	}{
+		{"keep current", types.CharsetPolicy{Preferred: []types.CodePage{types.CodePagePC858}}, chartablePC850},
+		{"switch to best", types.CharsetPolicy{Preferred: []types.CodePage{types.CodePagePC858}, SwitchToBest: true}, chartablePC858},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			enc := NewCharsetEncoder()
+			enc.SetCharset(chartablePC850)
+			if err := enc.SetCharsetPolicy(tt.policy); err != nil {
+				t.Fatalf("SetCharsetPolicy: %v", err)
+			}
+			mock := newMockDisplay()
+			mock.currentPage = chartablePC850
+
+			if _, err := enc.EncodeTextWithAutoCharsetSwitching("Ñ", mock); err != nil {
+				t.Fatalf("encode: %v", err)
+			}
+			if mock.currentPage != tt.wantPage {
+				t.Errorf("page = %d, want %d", mock.currentPage, tt.wantPage)
+			}
+			// ASCII text never causes a switch.
+			switches := mock.switchCount
+			if _, err := enc.EncodeTextWithAutoCharsetSwitching("abc", mock); err != nil {
+				t.Fatalf("encode: %v", err)
+			}
+			if mock.switchCount != switches {
+				t.Errorf("ASCII text switched code tables")
+			}
+		})
+	}
+}
+
+func TestPlanCoversWholeScreen(t *testing.T) {
+	lines := []string{"Obrigado, João", "Total: 5€"}
+
+	// Line by line, the second line forces a second switch.
+	enc := NewCharsetEncoder()
+	mock := newMockDisplay()
+	for _, line := range lines {
+		if _, err := enc.EncodeTextWithAutoCharsetSwitching(line, mock); err != nil {
+			t.Fatalf("encode %q: %v", line, err)
+		}
+	}
+	if mock.switchCount != 2 {
+		t.Fatalf("unplanned: %d switches, want 2", mock.switchCount)
+	}
+
+	enc = NewCharsetEncoder()
+	mock = newMockDisplay()
+	if err := enc.Plan(lines, mock); err != nil {
+		t.Fatalf("Plan: %v", err)
+	}
+	for _, line := range lines {
+		encoded, err := enc.EncodeTextWithAutoCharsetSwitching(line, mock)
+		if err != nil {
+			t.Fatalf("encode %q: %v", line, err)
+		}
+		if countByte(encoded, '?') != 0 {
+			t.Errorf("%q encoded with replacements: %q", line, encoded)
+		}
+	}
+	if mock.switchCount != 1 || mock.currentPage != chartablePC858 {
+		t.Errorf("planned: %d switches to page %d, want 1 to PC858", mock.switchCount, mock.currentPage)
+	}
+}
+
+func TestBestCoveringPageWhenNoneCoversAll(t *testing.T) {
+	enc := NewCharsetEncoder()
+	mock := newMockDisplay()
+
+	encoded, err := enc.EncodeTextWithAutoCharsetSwitching("Ação 日", mock)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if mock.currentPage != chartablePC860 {
+		t.Errorf("page = %d, want PC860 (%d)", mock.currentPage, chartablePC860)
+	}
+	if want := []byte{'A', 0x87, 0x84, 'o', ' ', '?'}; string(encoded) != string(want) {
+		t.Errorf("encoded = % X, want % X", encoded, want)
+	}
+}
@@ -1,10 +1,22 @@
 package escpos
 
+import "github.com/corrreia/govfd/types"
+
 // Character code table page constants (INTERNAL USE ONLY).
-// Latin character sets only - automatically selected by smart encoding
+// Standard ESC/POS numbering, used when a model declares no table of its own.
 const (
-	chartablePC437 = 0  // PC437: USA, Standard Europe (default)
-	chartablePC850 = 2  // PC850: Multilingual Latin
-	chartablePC860 = 3  // PC860: Portuguese
-	chartablePC858 = 19 // PC858: Euro
+	chartablePC437    = 0  // PC437: USA, Standard Europe (default)
+	chartableKatakana = 1  // Katakana: Japanese half-width Katakana
+	chartablePC850    = 2  // PC850: Multilingual Latin
+	chartablePC860    = 3  // PC860: Portuguese
+	chartablePC858    = 19 // PC858: Euro
 )
+
+// defaultCodePages is the standard ESC/POS code table numbering.
+var defaultCodePages = types.CodePageTable{
+	types.CodePagePC437:    chartablePC437,
+	types.CodePageKatakana: chartableKatakana,
+	types.CodePagePC850:    chartablePC850,
+	types.CodePagePC860:    chartablePC860,
+	types.CodePagePC858:    chartablePC858,
+}
@@ -1,5 +1,12 @@
 package escpos
 
+import (
+	"errors"
+	"fmt"
+
+	"github.com/corrreia/govfd/types"
+)
+
 // ESCPOSProtocol implements the Protocol interface for ESC/POS displays.
 type ESCPOSProtocol struct{}
 
@@ -14,49 +21,121 @@ func (p *ESCPOSProtocol) GetDescription() string {
 }
 
 // Clear returns the command sequence to initialize/clear the display.
-func (p *ESCPOSProtocol) Clear() []byte {
-	return SeqClear
+func (p *ESCPOSProtocol) Clear() ([]byte, error) {
+	return SeqClear, nil
 }
 
 // FormFeed returns the command sequence to clear screen content.
-func (p *ESCPOSProtocol) FormFeed() []byte {
-	return SeqFormFeed
+func (p *ESCPOSProtocol) FormFeed() ([]byte, error) {
+	return SeqFormFeed, nil
 }
 
 // MoveCursor returns the command sequence to move cursor to position (1-based).
-func (p *ESCPOSProtocol) MoveCursor(column, row int) []byte {
+func (p *ESCPOSProtocol) MoveCursor(column, row int) ([]byte, error) {
 	if column < 1 || column > 255 || row < 1 || row > 255 {
-		return nil // Invalid position
+		return nil, errors.New("column/row must be between 1 and 255")
 	}
-	return BuildSetCursorSeq(byte(column), byte(row))
+	return BuildSetCursorSeq(byte(column), byte(row)), nil
 }
 
 // SetBrightness returns the command sequence to set brightness level.
-func (p *ESCPOSProtocol) SetBrightness(level int) []byte {
+func (p *ESCPOSProtocol) SetBrightness(level int) ([]byte, error) {
 	if level < 1 || level > 4 {
-		return nil // Invalid brightness level
+		return nil, errors.New("brightness level must be between 1 and 4")
 	}
-	return BuildSetBrightnessSeq(byte(level))
+	return BuildSetBrightnessSeq(byte(level)), nil
 }
 
+// blinkStepMs is the unit of the US E blink period.
+const blinkStepMs = 50
+
 // SetBlink returns the command sequence to set cursor blink period.
-func (p *ESCPOSProtocol) SetBlink(intervalMs int) []byte {
-	if intervalMs < 0 {
-		return nil // Invalid interval
+func (p *ESCPOSProtocol) SetBlink(intervalMs int) ([]byte, error) {
+	if intervalMs < 0 || intervalMs > 255*blinkStepMs {
+		return nil, errors.New("blink interval must be between 0 and 12750 ms")
 	}
-	steps := byte(intervalMs / 50) // ESC/POS uses 50ms steps
-	return BuildSetBlinkSeq(steps)
+	steps := byte(intervalMs / blinkStepMs)
+	return BuildSetBlinkSeq(steps), nil
 }
 
 // SetCharset returns the command sequence to set character encoding table.
-func (p *ESCPOSProtocol) SetCharset(page int) []byte {
+func (p *ESCPOSProtocol) SetCharset(page int) ([]byte, error) {
 	if page < 0 || page > 255 {
-		return nil // Invalid charset page
+		return nil, errors.New("page must be between 0 and 255")
 	}
-	return BuildSetCharsetSeq(byte(page))
+	return BuildSetCharsetSeq(byte(page)), nil
+}
+
+// SetInternationalCharset returns the command sequence to select an
+// international character set.
+func (p *ESCPOSProtocol) SetInternationalCharset(charset types.InternationalCharset) ([]byte, error) {
+	intl, ok := lookupInternationalCharset(charset)
+	if !ok {
+		return nil, fmt.Errorf("unknown international character set %q", charset)
+	}
+	return BuildSetInternationalSeq(intl.n), nil
+}
+
+// SetDoubleByteMode returns the command sequence to enter or leave
+// double-byte (Kanji) character mode.
+func (p *ESCPOSProtocol) SetDoubleByteMode(enabled bool) ([]byte, error) {
+	if enabled {
+		return SeqKanjiModeOn, nil
+	}
+	return SeqKanjiModeOff, nil
+}
+
+// DefineGlyph returns the command sequence to download a user-defined
+// character at code and activate the user-defined character set.
+func (p *ESCPOSProtocol) DefineGlyph(code byte, glyph types.Glyph) ([]byte, error) {
+	if code < 0x20 {
+		return nil, fmt.Errorf("control code %#02x cannot hold a glyph", code)
+	}
+	return BuildDefineGlyphSeq(code, glyph), nil
+}
+
+// CancelGlyph returns the command sequence to delete the user-defined
+// character at code.
+func (p *ESCPOSProtocol) CancelGlyph(code byte) ([]byte, error) {
+	if code < 0x20 {
+		return nil, fmt.Errorf("control code %#02x cannot hold a glyph", code)
+	}
+	return BuildCancelGlyphSeq(code), nil
+}
+
+// SetReverse returns the command sequence to turn reverse characters on or off.
+func (p *ESCPOSProtocol) SetReverse(enabled bool) ([]byte, error) {
+	return BuildSetReverseSeq(enabled), nil
+}
+
+// SetDisplayMode returns the command sequence to select overwrite, vertical
+// scroll or horizontal scroll mode.
+func (p *ESCPOSProtocol) SetDisplayMode(mode types.DisplayMode) ([]byte, error) {
+	switch mode {
+	case types.DisplayModeOverwrite:
+		return BuildSetDisplayModeSeq(CmdUSOverwriteMode), nil
+	case types.DisplayModeVerticalScroll:
+		return BuildSetDisplayModeSeq(CmdUSVerticalScrollMode), nil
+	case types.DisplayModeHorizontalScroll:
+		return BuildSetDisplayModeSeq(CmdUSHorizontalScrollMode), nil
+	}
+	return nil, fmt.Errorf("unknown display mode %q", mode)
+}
+
+// SetClock returns the command sequence to set the built-in clock and show it.
+func (p *ESCPOSProtocol) SetClock(hour, minute int) ([]byte, error) {
+	if hour < 0 || hour > 23 || minute < 0 || minute > 59 {
+		return nil, errors.New("time must be between 00:00 and 23:59")
+	}
+	return BuildSetClockSeq(byte(hour), byte(minute)), nil
+}
+
+// ShowClock returns the command sequence to show the built-in clock.
+func (p *ESCPOSProtocol) ShowClock() ([]byte, error) {
+	return SeqShowClock, nil
 }
 
 // SelfTest returns the command sequence to execute self-test.
-func (p *ESCPOSProtocol) SelfTest() []byte {
-	return SeqSelfTest
+func (p *ESCPOSProtocol) SelfTest() ([]byte, error) {
+	return SeqSelfTest, nil
 }
@@ -1,13 +1,30 @@
 package escpos
 
+import "github.com/corrreia/govfd/types"
+
 // ESC/POS Command byte constants for VFD display operations.
 // These represent the hexadecimal command sequences used to control ESC/POS compatible VFD displays.
 // Reference: https://download4.epson.biz/sec_pubs/pos/reference_en/escpos_dm/commands.html
 
 // ASCII Control Characters
 const (
+	// Backspace - moves the cursor left
+	CmdBackspace = 0x08 // BS
+
+	// Horizontal Tab - moves the cursor right
+	CmdHorizontalTab = 0x09 // HT
+
+	// Line Feed - moves the cursor down
+	CmdLineFeed = 0x0A // LF
+
+	// Home - moves the cursor to the top left
+	CmdHome = 0x0B // HOM
+
 	// Form Feed - clears the screen
 	CmdFormFeed = 0x0C
+
+	// Carriage Return - moves the cursor to the start of the line
+	CmdCarriageReturn = 0x0D // CR
 )
 
 // Escape Sequence Prefixes
@@ -17,6 +34,9 @@ const (
 
 	// Unit Separator - used as prefix for device-specific commands
 	CmdUnitSeparator = 0x1F // US
+
+	// File Separator - used as prefix for double-byte (Kanji) commands
+	CmdFileSeparator = 0x1C // FS
 )
 
 // Escape Sequence Commands (ESC + command)
@@ -26,10 +46,31 @@ const (
 
 	// ESC t - Set character code table page
 	CmdEscCharsetTable = 0x74 // t - used with ESC (0x1B)
+
+	// ESC & - Define user-defined characters (followed by y c1 c2 [x d1...dx]...)
+	CmdEscDefineGlyph = 0x26 // & - used with ESC (0x1B)
+
+	// ESC % - Select/cancel user-defined character set (followed by n)
+	CmdEscSelectGlyphs = 0x25 // % - used with ESC (0x1B)
+
+	// ESC ? - Cancel a user-defined character (followed by the character code)
+	CmdEscCancelGlyph = 0x3F // ? - used with ESC (0x1B)
+
+	// ESC R - Select international character set (followed by n)
+	CmdEscInternational = 0x52 // R - used with ESC (0x1B)
 )
 
 // Unit Separator Commands (US + command)
 const (
+	// US MD1 - Overwrite mode
+	CmdUSOverwriteMode = 0x01 // MD1 - used with US (0x1F)
+
+	// US MD2 - Vertical scroll mode
+	CmdUSVerticalScrollMode = 0x02 // MD2 - used with US (0x1F)
+
+	// US MD3 - Horizontal scroll mode
+	CmdUSHorizontalScrollMode = 0x03 // MD3 - used with US (0x1F)
+
 	// US $ - Set cursor position (followed by column, row bytes)
 	CmdUSSetCursor = 0x24 // $ - used with US (0x1F)
 
@@ -39,8 +80,26 @@ const (
 	// US E - Set cursor blink period (followed by step value)
 	CmdUSSetBlink = 0x45 // E - used with US (0x1F)
 
+	// US T - Set and display the time (followed by hour, minute)
+	CmdUSSetClock = 0x54 // T - used with US (0x1F)
+
+	// US U - Display the time
+	CmdUSShowClock = 0x55 // U - used with US (0x1F)
+
 	// US X - Set brightness level (followed by level 1-4)
 	CmdUSSetBrightness = 0x58 // X - used with US (0x1F)
+
+	// US r - Turn reverse mode on/off (followed by n)
+	CmdUSReverse = 0x72 // r - used with US (0x1F)
+)
+
+// File Separator Commands (FS + command)
+const (
+	// FS & - Select double-byte (Kanji) character mode
+	CmdFSKanjiModeOn = 0x26 // & - used with FS (0x1C)
+
+	// FS . - Cancel double-byte (Kanji) character mode
+	CmdFSKanjiModeOff = 0x2E // . - used with FS (0x1C)
 )
 
 // Complete Command Sequences as byte arrays for convenience
@@ -53,6 +112,15 @@ var (
 
 	// Self-test: US @
 	SeqSelfTest = []byte{CmdUnitSeparator, CmdUSSelfTest}
+
+	// Display the time: US U
+	SeqShowClock = []byte{CmdUnitSeparator, CmdUSShowClock}
+
+	// Enter double-byte (Kanji) character mode: FS &
+	SeqKanjiModeOn = []byte{CmdFileSeparator, CmdFSKanjiModeOn}
+
+	// Leave double-byte (Kanji) character mode: FS .
+	SeqKanjiModeOff = []byte{CmdFileSeparator, CmdFSKanjiModeOff}
 )
 
 // Command sequence builders (return byte arrays for specific operations)
@@ -75,8 +143,63 @@ func BuildSetBlinkSeq(steps byte) []byte {
 	return []byte{CmdUnitSeparator, CmdUSSetBlink, steps}
 }
 
+// BuildSetReverseSeq creates the command sequence to turn reverse mode on or off.
+// Returns: US r n
+func BuildSetReverseSeq(enabled bool) []byte {
+	var n byte
+	if enabled {
+		n = 1
+	}
+	return []byte{CmdUnitSeparator, CmdUSReverse, n}
+}
+
+// BuildSetDisplayModeSeq creates the command sequence to select a display mode.
+// Returns: US MDn
+func BuildSetDisplayModeSeq(mode byte) []byte {
+	return []byte{CmdUnitSeparator, mode}
+}
+
+// BuildSetClockSeq creates the command sequence to set and display the time.
+// Returns: US T hour minute
+func BuildSetClockSeq(hour, minute byte) []byte {
+	return []byte{CmdUnitSeparator, CmdUSSetClock, hour, minute}
+}
+
 // BuildSetCharsetSeq creates the command sequence to set character code table.
 // Returns: ESC t page
 func BuildSetCharsetSeq(page byte) []byte {
 	return []byte{CmdEscape, CmdEscCharsetTable, page}
 }
+
+// BuildSetInternationalSeq creates the command sequence to select an
+// international character set.
+// Returns: ESC R n
+func BuildSetInternationalSeq(n byte) []byte {
+	return []byte{CmdEscape, CmdEscInternational, n}
+}
+
+// BuildDefineGlyphSeq creates the command sequence to define a single 5x7
+// user-defined character at code and select the user-defined character set.
+// Returns: ESC & 1 code code 5 d1..d5 ESC % 1
+//
+// Each data byte is one dot column, left to right, with bit 7 the top dot.
+func BuildDefineGlyphSeq(code byte, glyph types.Glyph) []byte {
+	seq := []byte{CmdEscape, CmdEscDefineGlyph, 1, code, code, types.GlyphWidth}
+	for col := 0; col < types.GlyphWidth; col++ {
+		var column byte
+		for row := 0; row < types.GlyphHeight; row++ {
+			if glyph[row]&(1<<(types.GlyphWidth-1-col)) != 0 {
+				column |= 0x80 >> row
+			}
+		}
+		seq = append(seq, column)
+	}
+	return append(seq, CmdEscape, CmdEscSelectGlyphs, 1)
+}
+
+// BuildCancelGlyphSeq creates the command sequence to delete the user-defined
+// character at code, restoring the built-in character.
+// Returns: ESC ? code
+func BuildCancelGlyphSeq(code byte) []byte {
+	return []byte{CmdEscape, CmdEscCancelGlyph, code}
+}
@@ -0,0 +1,475 @@
+package escpos
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/corrreia/govfd/types"
+)
+
+// Command is one command decoded from an ESC/POS byte stream.
+type Command interface {
+	String() string
+}
+
+// Initialize is ESC @: reset the display to its power-on state.
+type Initialize struct{}
+
+// ClearScreen is FF: clear the screen.
+type ClearScreen struct{}
+
+// MoveCursor is US $: move the cursor to a 1-based position.
+type MoveCursor struct {
+	Column, Row int
+}
+
+// SetBrightness is US X: set the brightness level.
+type SetBrightness struct {
+	Level int
+}
+
+// SetBlink is US E: set the blink interval.
+type SetBlink struct {
+	IntervalMs int
+}
+
+// SelectCodeTable is ESC t: select a character code table by device page.
+type SelectCodeTable struct {
+	Page int
+}
+
+// SelectInternational is ESC R: select an international character set.
+type SelectInternational struct {
+	Charset types.InternationalCharset
+}
+
+// DoubleByteMode is FS & or FS .: enter or leave Kanji mode.
+type DoubleByteMode struct {
+	Enabled bool
+}
+
+// DefineGlyphs is ESC &: download user-defined characters from code First on.
+type DefineGlyphs struct {
+	First  byte
+	Glyphs []types.Glyph
+}
+
+// SelectGlyphs is ESC %: select or cancel the user-defined character set.
+type SelectGlyphs struct {
+	Enabled bool
+}
+
+// CancelGlyph is ESC ?: delete the user-defined character at Code.
+type CancelGlyph struct {
+	Code byte
+}
+
+// SetReverse is US r: turn reverse mode on or off.
+type SetReverse struct {
+	Enabled bool
+}
+
+// SetDisplayMode is US MD1, MD2 or MD3: select what happens at the end of
+// the screen.
+type SetDisplayMode struct {
+	Mode types.DisplayMode
+}
+
+// SetClock is US T: set and show the time.
+type SetClock struct {
+	Hour, Minute int
+}
+
+// ShowClock is US U: show the time.
+type ShowClock struct{}
+
+// SelfTest is US @: run the self-test.
+type SelfTest struct{}
+
+// Text is a run of characters, decoded to UTF-8 with the code table,
+// international set and Kanji mode the stream selected. User-defined
+// characters decode as U+FFFD. Cursor controls (BS, HT, LF, HOM, CR) are
+// kept as they are.
+type Text struct {
+	Text string
+}
+
+// Unknown is a sequence the decoder does not understand, or a command cut
+// off by the end of the stream.
+type Unknown struct {
+	Reason string
+}
+
+func (Initialize) String() string  { return "Initialize" }
+func (ClearScreen) String() string { return "ClearScreen" }
+func (c MoveCursor) String() string {
+	return fmt.Sprintf("MoveCursor{Column: %d, Row: %d}", c.Column, c.Row)
+}
+func (c SetBrightness) String() string { return fmt.Sprintf("SetBrightness{Level: %d}", c.Level) }
+func (c SetBlink) String() string      { return fmt.Sprintf("SetBlink{IntervalMs: %d}", c.IntervalMs) }
+func (c SelectCodeTable) String() string {
+	return fmt.Sprintf("SelectCodeTable{Page: %d}", c.Page)
+}
+func (c SelectInternational) String() string {
+	return fmt.Sprintf("SelectInternational{Charset: %s}", c.Charset)
+}
+func (c DoubleByteMode) String() string { return fmt.Sprintf("DoubleByteMode{Enabled: %t}", c.Enabled) }
+func (c DefineGlyphs) String() string {
+	return fmt.Sprintf("DefineGlyphs{First: %#02x, Count: %d}", c.First, len(c.Glyphs))
+}
+func (c SelectGlyphs) String() string { return fmt.Sprintf("SelectGlyphs{Enabled: %t}", c.Enabled) }
+func (c CancelGlyph) String() string  { return fmt.Sprintf("CancelGlyph{Code: %#02x}", c.Code) }
+func (c SetReverse) String() string   { return fmt.Sprintf("SetReverse{Enabled: %t}", c.Enabled) }
+func (c SetDisplayMode) String() string {
+	return fmt.Sprintf("SetDisplayMode{Mode: %s}", c.Mode)
+}
+func (c SetClock) String() string {
+	return fmt.Sprintf("SetClock{Hour: %d, Minute: %d}", c.Hour, c.Minute)
+}
+func (ShowClock) String() string { return "ShowClock" }
+func (SelfTest) String() string  { return "SelfTest" }
+func (c Text) String() string    { return fmt.Sprintf("Text{%q}", c.Text) }
+func (c Unknown) String() string { return "Unknown (" + c.Reason + ")" }
+
+// Decoded is a command together with the bytes it was decoded from.
+type Decoded struct {
+	Offset  int    // Position of the first byte in the stream
+	Raw     []byte // The command's bytes
+	Command Command
+}
+
+// Decoder decodes ESC/POS display byte streams into commands, e.g. to read
+// field logs. It follows the code table, international set and Kanji mode
+// the stream selects, so that text decodes as the display would show it.
+type Decoder struct {
+	codePages  types.CodePageTable
+	doubleByte types.DoubleByteCharset
+
+	page           int
+	international  *internationalCharset
+	doubleByteMode bool
+	glyphsSelected bool
+	glyphs         [256]bool // Codes holding a user-defined character
+}
+
+// NewDecoder creates a decoder for the standard ESC/POS code table
+// numbering, in the power-on state.
+func NewDecoder() *Decoder {
+	d := &Decoder{codePages: defaultCodePages}
+	d.Reset()
+	return d
+}
+
+// SetCodePages sets the model's code tables and their device page numbers,
+// and resets the decoder to the power-on state.
+func (d *Decoder) SetCodePages(pages types.CodePageTable) error {
+	if err := validateCodePages(pages); err != nil {
+		return err
+	}
+	d.codePages = maps.Clone(pages)
+	d.Reset()
+	return nil
+}
+
+// SetDoubleByteCharset sets the double-byte character set of Kanji mode.
+func (d *Decoder) SetDoubleByteCharset(charset types.DoubleByteCharset) error {
+	if charset != types.DoubleByteNone && doubleByteCharsets[charset] == nil {
+		return fmt.Errorf("unsupported double-byte charset: %s", charset)
+	}
+	d.doubleByte = charset
+	return nil
+}
+
+// Reset restores the power-on state, as ESC @ does.
+func (d *Decoder) Reset() {
+	d.page = powerOnPage(d.codePages)
+	d.international = &internationalCharsets[0]
+	d.doubleByteMode = false
+	d.glyphsSelected = false
+	d.glyphs = [256]bool{}
+}
+
+// Decode decodes stream. Sequences it does not understand become Unknown
+// commands and decoding carries on after them. The decoder keeps its state
+// between calls, but a command split across two calls decodes as Unknown.
+func (d *Decoder) Decode(stream []byte) []Decoded {
+	var decoded []Decoded
+	for offset := 0; offset < len(stream); {
+		n, cmd := d.next(stream[offset:])
+		decoded = append(decoded, Decoded{Offset: offset, Raw: stream[offset : offset+n], Command: cmd})
+		offset += n
+	}
+	return decoded
+}
+
+// next decodes the command at the start of b and returns its length.
+func (d *Decoder) next(b []byte) (int, Command) {
+	switch b[0] {
+	case CmdFormFeed:
+		return 1, ClearScreen{}
+	case CmdEscape:
+		return d.nextEscape(b)
+	case CmdUnitSeparator:
+		return d.nextUnitSeparator(b)
+	case CmdFileSeparator:
+		return d.nextFileSeparator(b)
+	}
+	n := 0
+	for n < len(b) && isTextByte(b[n]) {
+		n++
+	}
+	if n == 0 {
+		return 1, Unknown{fmt.Sprintf("control code %#02x", b[0])}
+	}
+	return n, Text{d.decodeText(b[:n])}
+}
+
+// truncated reports a command cut off by the end of the stream.
+func truncated(b []byte) (int, Command) {
+	return len(b), Unknown{fmt.Sprintf("truncated %s", mnemonic(b))}
+}
+
+func (d *Decoder) nextEscape(b []byte) (int, Command) {
+	if len(b) < 2 {
+		return truncated(b)
+	}
+	if b[1] == CmdEscInitialize {
+		d.Reset()
+		return 2, Initialize{}
+	}
+	if b[1] == CmdEscDefineGlyph {
+		return d.defineGlyphs(b)
+	}
+	switch b[1] {
+	case CmdEscCharsetTable, CmdEscInternational, CmdEscSelectGlyphs, CmdEscCancelGlyph:
+	default:
+		return 2, Unknown{fmt.Sprintf("ESC %#02x", b[1])}
+	}
+	if len(b) < 3 {
+		return truncated(b)
+	}
+	n := b[2]
+	switch b[1] {
+	case CmdEscCharsetTable:
+		d.page = int(n)
+		return 3, SelectCodeTable{Page: int(n)}
+	case CmdEscInternational:
+		if int(n) >= len(internationalCharsets) {
+			return 3, Unknown{fmt.Sprintf("ESC R with unknown international set %d", n)}
+		}
+		d.international = &internationalCharsets[n]
+		return 3, SelectInternational{Charset: d.international.id}
+	case CmdEscSelectGlyphs:
+		d.glyphsSelected = n&1 == 1
+		return 3, SelectGlyphs{Enabled: d.glyphsSelected}
+	default: // CmdEscCancelGlyph
+		d.glyphs[n] = false
+		return 3, CancelGlyph{Code: n}
+	}
+}
+
+// defineGlyphs decodes ESC & y c1 c2 [x d1..d(x*y)]... for y = 1, the
+// height of 5x7 character displays.
+func (d *Decoder) defineGlyphs(b []byte) (int, Command) {
+	if len(b) < 5 {
+		return truncated(b)
+	}
+	y, first, last := b[2], b[3], b[4]
+	if y != 1 || first > last || first < 0x20 {
+		return 5, Unknown{fmt.Sprintf("ESC & with y=%d, codes %#02x..%#02x", y, first, last)}
+	}
+	cmd := DefineGlyphs{First: first}
+	n := 5
+	for code := int(first); code <= int(last); code++ {
+		if n >= len(b) || n+1+int(b[n]) > len(b) {
+			return truncated(b)
+		}
+		width := int(b[n])
+		if width > types.GlyphWidth {
+			return n + 1, Unknown{fmt.Sprintf("ESC & glyph %d dots wide", width)}
+		}
+		cmd.Glyphs = append(cmd.Glyphs, glyphFromColumns(b[n+1:n+1+width]))
+		d.glyphs[code] = true
+		n += 1 + width
+	}
+	return n, cmd
+}
+
+// glyphFromColumns converts ESC & dot columns, bit 7 the top dot, into a
+// Glyph. It is the inverse of BuildDefineGlyphSeq.
+func glyphFromColumns(columns []byte) types.Glyph {
+	var glyph types.Glyph
+	for col, column := range columns {
+		for row := 0; row < types.GlyphHeight; row++ {
+			if column&(0x80>>row) != 0 {
+				glyph[row] |= 1 << (types.GlyphWidth - 1 - col)
+			}
+		}
+	}
+	return glyph
+}
+
+func (d *Decoder) nextUnitSeparator(b []byte) (int, Command) {
+	if len(b) < 2 {
+		return truncated(b)
+	}
+	switch b[1] {
+	case CmdUSOverwriteMode:
+		return 2, SetDisplayMode{Mode: types.DisplayModeOverwrite}
+	case CmdUSVerticalScrollMode:
+		return 2, SetDisplayMode{Mode: types.DisplayModeVerticalScroll}
+	case CmdUSHorizontalScrollMode:
+		return 2, SetDisplayMode{Mode: types.DisplayModeHorizontalScroll}
+	case CmdUSSelfTest:
+		return 2, SelfTest{}
+	case CmdUSShowClock:
+		return 2, ShowClock{}
+	case CmdUSSetCursor, CmdUSSetClock:
+		if len(b) < 4 {
+			return truncated(b)
+		}
+		if b[1] == CmdUSSetCursor {
+			return 4, MoveCursor{Column: int(b[2]), Row: int(b[3])}
+		}
+		return 4, SetClock{Hour: int(b[2]), Minute: int(b[3])}
+	case CmdUSSetBlink, CmdUSSetBrightness, CmdUSReverse:
+		if len(b) < 3 {
+			return truncated(b)
+		}
+		switch b[1] {
+		case CmdUSSetBlink:
+			return 3, SetBlink{IntervalMs: int(b[2]) * blinkStepMs}
+		case CmdUSSetBrightness:
+			return 3, SetBrightness{Level: int(b[2])}
+		default:
+			return 3, SetReverse{Enabled: b[2]&1 == 1}
+		}
+	}
+	return 2, Unknown{fmt.Sprintf("US %#02x", b[1])}
+}
+
+func (d *Decoder) nextFileSeparator(b []byte) (int, Command) {
+	if len(b) < 2 {
+		return truncated(b)
+	}
+	switch b[1] {
+	case CmdFSKanjiModeOn:
+		d.doubleByteMode = true
+		return 2, DoubleByteMode{Enabled: true}
+	case CmdFSKanjiModeOff:
+		d.doubleByteMode = false
+		return 2, DoubleByteMode{Enabled: false}
+	}
+	return 2, Unknown{fmt.Sprintf("FS %#02x", b[1])}
+}
+
+// isTextByte reports whether b is shown as a character or moves the cursor
+// like one, rather than starting a command.
+func isTextByte(b byte) bool {
+	switch b {
+	case CmdBackspace, CmdHorizontalTab, CmdLineFeed, CmdHome, CmdCarriageReturn:
+		return true
+	}
+	return b >= 0x20
+}
+
+// decodeText decodes a run of text bytes in the current state.
+func (d *Decoder) decodeText(text []byte) string {
+	if d.doubleByteMode && d.doubleByte != types.DoubleByteNone {
+		decoded, err := doubleByteCharsets[d.doubleByte].encoding.NewDecoder().Bytes(text)
+		if err == nil {
+			return string(decoded)
+		}
+	}
+	codePage, _ := d.codePageFor(d.page)
+	var sb strings.Builder
+	sb.Grow(len(text))
+	for _, b := range text {
+		sb.WriteRune(d.decodeByte(codePage, b))
+	}
+	return sb.String()
+}
+
+// decodeByte returns the character the display shows for b.
+func (d *Decoder) decodeByte(codePage types.CodePage, b byte) rune {
+	switch {
+	case b < 0x20:
+		return rune(b)
+	case d.glyphsSelected && d.glyphs[b]:
+		return utf8.RuneError
+	case codePage == types.CodePageHD44780:
+		return hd44780Rune(b)
+	case b < utf8.RuneSelf:
+		for i, pos := range internationalPositions {
+			if pos == b {
+				return d.international.chars[i]
+			}
+		}
+		return rune(b)
+	case codePage == types.CodePageKatakana:
+		if b >= katakanaByteOffset && b <= katakanaByteOffset+(halfWidthKatakanaLast-halfWidthKatakanaFirst) {
+			return halfWidthKatakanaFirst + rune(b-katakanaByteOffset)
+		}
+	case latinCodePages[codePage] != nil:
+		return latinCodePages[codePage].DecodeByte(b)
+	}
+	return utf8.RuneError
+}
+
+// codePageFor returns which code table device page selects.
+func (d *Decoder) codePageFor(page int) (types.CodePage, bool) {
+	for codePage, p := range d.codePages {
+		if p == page {
+			return codePage, true
+		}
+	}
+	return "", false
+}
+
+// mnemonic names the command at the start of raw, e.g. "US $".
+func mnemonic(raw []byte) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	prefix := map[byte]string{CmdEscape: "ESC", CmdUnitSeparator: "US", CmdFileSeparator: "FS"}[raw[0]]
+	switch {
+	case raw[0] == CmdFormFeed:
+		return "FF"
+	case prefix == "":
+		return ""
+	case len(raw) < 2:
+		return prefix
+	case raw[1] >= 0x21 && raw[1] < 0x7F:
+		return prefix + " " + string(rune(raw[1]))
+	default:
+		return fmt.Sprintf("%s %#02x", prefix, raw[1])
+	}
+}
+
+// Dump writes one line per decoded command: offset, bytes, mnemonic and
+// command, e.g.
+//
+//	0002  1F 24 05 02        US $   MoveCursor{Column: 5, Row: 2}
+func Dump(w io.Writer, decoded []Decoded) error {
+	for _, dc := range decoded {
+		raw := fmt.Sprintf("% X", dc.Raw)
+		if len(dc.Raw) > 6 {
+			raw = fmt.Sprintf("% X ...", dc.Raw[:5])
+		}
+		if _, err := fmt.Fprintf(w, "%04X  %-18s %-6s %s\n", dc.Offset, raw, mnemonic(dc.Raw), dc.Command); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Disassemble decodes stream from the power-on state with the standard code
+// table numbering and returns its dump.
+func Disassemble(stream []byte) string {
+	var sb strings.Builder
+	Dump(&sb, NewDecoder().Decode(stream))
+	return sb.String()
+}
@@ -0,0 +1,225 @@
+package escpos
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/corrreia/govfd/types"
+)
+
+// streamSwitcher appends the commands an encoder sends to a byte stream.
+type streamSwitcher struct {
+	protocol ESCPOSProtocol
+	stream   []byte
+}
+
+func (s *streamSwitcher) add(cmd []byte, err error) error {
+	s.stream = append(s.stream, cmd...)
+	return err
+}
+
+func (s *streamSwitcher) SelectCodeTable(page int) error {
+	return s.add(s.protocol.SetCharset(page))
+}
+
+func (s *streamSwitcher) SetDoubleByteMode(enabled bool) error {
+	return s.add(s.protocol.SetDoubleByteMode(enabled))
+}
+
+func (s *streamSwitcher) SelectInternationalCharset(charset types.InternationalCharset) error {
+	return s.add(s.protocol.SetInternationalCharset(charset))
+}
+
+func (s *streamSwitcher) DefineGlyph(code byte, glyph types.Glyph) error {
+	return s.add(s.protocol.DefineGlyph(code, glyph))
+}
+
+func (s *streamSwitcher) CancelGlyph(code byte) error {
+	return s.add(s.protocol.CancelGlyph(code))
+}
+
+// commandsOf returns the commands of decoded.
+func commandsOf(decoded []Decoded) []Command {
+	commands := make([]Command, len(decoded))
+	for i, dc := range decoded {
+		commands[i] = dc.Command
+	}
+	return commands
+}
+
+func TestDecodeEncodedStream(t *testing.T) {
+	s := &streamSwitcher{}
+	s.add(s.protocol.Clear())
+	s.add(s.protocol.MoveCursor(1, 2))
+	s.add(s.protocol.SetBrightness(3))
+	enc := NewCharsetEncoder()
+	text, err := enc.EncodeTextWithAutoCharsetSwitching("Ação 5€", s)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	s.stream = append(s.stream, text...)
+
+	got := commandsOf(NewDecoder().Decode(s.stream))
+	want := []Command{
+		Initialize{},
+		MoveCursor{Column: 1, Row: 2},
+		SetBrightness{Level: 3},
+		SelectCodeTable{Page: chartablePC858},
+		Text{"Ação 5€"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("decoded %v, want %v", got, want)
+	}
+}
+
+func TestDecodeReportsUnknownSequences(t *testing.T) {
+	stream := []byte{'A', CmdEscape, 0x99, 'B', 0x01, CmdUnitSeparator, CmdUSSetCursor, 1}
+
+	decoded := NewDecoder().Decode(stream)
+	want := []struct {
+		offset int
+		cmd    Command
+	}{
+		{0, Text{"A"}},
+		{1, Unknown{"ESC 0x99"}},
+		{3, Text{"B"}},
+		{4, Unknown{"control code 0x01"}},
+		{5, Unknown{"truncated US $"}},
+	}
+	if len(decoded) != len(want) {
+		t.Fatalf("decoded %v, want %d commands", commandsOf(decoded), len(want))
+	}
+	for i, w := range want {
+		if decoded[i].Offset != w.offset || decoded[i].Command != w.cmd {
+			t.Errorf("command %d = %v at %d, want %v at %d", i, decoded[i].Command, decoded[i].Offset, w.cmd, w.offset)
+		}
+	}
+}
+
+func TestDecodeFollowsCharsetState(t *testing.T) {
+	d := NewDecoder()
+	if err := d.SetDoubleByteCharset(types.DoubleByteShiftJIS); err != nil {
+		t.Fatalf("SetDoubleByteCharset: %v", err)
+	}
+	stream := []byte{CmdEscape, CmdEscInternational, 2, '[', '}'}
+	stream = append(stream, BuildSetCharsetSeq(chartableKatakana)...)
+	stream = append(stream, 0xB1)
+	stream = append(stream, SeqKanjiModeOn...)
+	stream = append(stream, 0x93, 0xFA, 0x96, 0x7B) // 日本 in Shift-JIS
+	stream = append(stream, SeqKanjiModeOff...)
+	stream = append(stream, SeqClear...)
+	stream = append(stream, '[', 0xB1)
+
+	var texts []string
+	for _, dc := range d.Decode(stream) {
+		if text, ok := dc.Command.(Text); ok {
+			texts = append(texts, text.Text)
+		}
+	}
+	want := []string{"Äü", "ｱ", "日本", "[▒"}
+	if !reflect.DeepEqual(texts, want) {
+		t.Errorf("texts = %q, want %q", texts, want)
+	}
+}
+
+func TestDecoderSetCodePages(t *testing.T) {
+	d := NewDecoder()
+	if err := d.SetCodePages(types.CodePageTable{types.CodePagePC437: 0, types.CodePagePC850: 0}); err == nil {
+		t.Error("SetCodePages accepted two tables on one page")
+	}
+	pages := types.CodePageTable{types.CodePagePC437: 0, types.CodePagePC860: 3}
+	if err := d.SetCodePages(pages); err != nil {
+		t.Fatalf("SetCodePages: %v", err)
+	}
+	pages[types.CodePagePC860] = 5 // The decoder keeps its own copy
+
+	stream := append(BuildSetCharsetSeq(3), 0x84)
+	var texts []string
+	for _, dc := range d.Decode(stream) {
+		if text, ok := dc.Command.(Text); ok {
+			texts = append(texts, text.Text)
+		}
+	}
+	if want := []string{"ã"}; !reflect.DeepEqual(texts, want) {
+		t.Errorf("texts = %q, want %q", texts, want)
+	}
+}
+
+func TestDecoderPowerOnPageFollowsCodePages(t *testing.T) {
+	tests := []struct {
+		pages types.CodePageTable
+		want  string
+	}{
+		{types.CodePageTable{types.CodePageKatakana: 1}, "Aｱ"},                              // the only table
+		{types.CodePageTable{types.CodePagePC858: 0, types.CodePagePC437: 5}, "A\u2592"},    // PC437's page
+		{types.CodePageTable{types.CodePagePC858: 0, types.CodePageKatakana: 1}, "A\ufffd"}, // unknown
+	}
+	for _, tt := range tests {
+		d := NewDecoder()
+		if err := d.SetCodePages(tt.pages); err != nil {
+			t.Fatalf("SetCodePages(%v): %v", tt.pages, err)
+		}
+		// The encoder must agree on the page shown at power-on
+		enc := NewCharsetEncoder()
+		enc.SetCodePages(tt.pages)
+		if enc.currentCharset != d.page {
+			t.Errorf("%v: decoder page %d, encoder page %d", tt.pages, d.page, enc.currentCharset)
+		}
+		var texts []string
+		for _, dc := range d.Decode([]byte{'A', 0xB1}) {
+			if text, ok := dc.Command.(Text); ok {
+				texts = append(texts, text.Text)
+			}
+		}
+		if want := []string{tt.want}; !reflect.DeepEqual(texts, want) {
+			t.Errorf("%v: texts = %q, want %q", tt.pages, texts, want)
+		}
+	}
+}
+
+func TestDecodeGlyphs(t *testing.T) {
+	check := types.Glyph{0b00000, 0b00001, 0b00010, 0b10100, 0b01000, 0b00000, 0b00000}
+	stream := append(BuildDefineGlyphSeq(0xB0, check), 'x', 0xB0)
+	stream = append(stream, BuildCancelGlyphSeq(0xB0)...)
+	stream = append(stream, 0xB0)
+
+	got := commandsOf(NewDecoder().Decode(stream))
+	want := []Command{
+		DefineGlyphs{First: 0xB0, Glyphs: []types.Glyph{check}},
+		SelectGlyphs{Enabled: true},
+		Text{"x�"},
+		CancelGlyph{Code: 0xB0},
+		Text{"░"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("decoded %v, want %v", got, want)
+	}
+}
+
+func TestDisassemble(t *testing.T) {
+	stream := append([]byte{}, SeqClear...)
+	stream = append(stream, BuildSetCursorSeq(5, 2)...)
+	stream = append(stream, 'O', 'l', 0xA0, 'A', 'B', 'C', 'D')
+	want := "0000  1B 40              ESC @  Initialize\n" +
+		"0002  1F 24 05 02        US $   MoveCursor{Column: 5, Row: 2}\n" +
+		"0006  4F 6C A0 41 42 ...        Text{\"OláABCD\"}\n"
+	if got := Disassemble(stream); got != want {
+		t.Errorf("dump =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestDecoderHD44780(t *testing.T) {
+	d := NewDecoder()
+	if err := d.SetCodePages(types.CodePageTable{types.CodePageHD44780: 0}); err != nil {
+		t.Fatal(err)
+	}
+	var texts []string
+	for _, dc := range d.Decode([]byte{'A', 0x5C, 0x7E, 0xB1, 0xF7}) {
+		if text, ok := dc.Command.(Text); ok {
+			texts = append(texts, text.Text)
+		}
+	}
+	if want := []string{"A¥→ｱπ"}; !reflect.DeepEqual(texts, want) {
+		t.Errorf("texts = %q, want %q", texts, want)
+	}
+}
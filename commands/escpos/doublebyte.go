@@ -0,0 +1,153 @@
+package escpos
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/corrreia/govfd/types"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+	"golang.org/x/text/width"
+)
+
+// doubleByteCharset describes how runes are encoded in Kanji mode for one
+// Asian model variant.
+type doubleByteCharset struct {
+	encoding encoding.Encoding
+	// valid reports whether an encoded double-byte character lies inside the
+	// range the display ROM actually holds. The x/text encodings are supersets
+	// (GBK for GB2312, UHC for KS C 5601) of what the hardware supports.
+	valid func(encoded string) bool
+}
+
+// doubleByteCharsets maps each supported variant to its Kanji mode encoding.
+var doubleByteCharsets = map[types.DoubleByteCharset]*doubleByteCharset{
+	types.DoubleByteGB2312:   {encoding: simplifiedchinese.GBK, valid: isEUCPair},
+	types.DoubleByteBig5:     {encoding: traditionalchinese.Big5},
+	types.DoubleByteKSC5601:  {encoding: korean.EUCKR, valid: isEUCPair},
+	types.DoubleByteShiftJIS: {encoding: japanese.ShiftJIS},
+}
+
+// isEUCPair reports whether a double-byte character uses the EUC range
+// (both bytes 0xA1..0xFE) shared by GB2312 and KS C 5601.
+func isEUCPair(encoded string) bool {
+	return len(encoded) != 2 || (encoded[0] >= 0xA1 && encoded[0] <= 0xFE && encoded[1] >= 0xA1 && encoded[1] <= 0xFE)
+}
+
+// SetDoubleByteCharset configures the double-byte character set of the
+// attached model variant. Pass types.DoubleByteNone for single-byte models.
+func (e *CharsetEncoder) SetDoubleByteCharset(charset types.DoubleByteCharset) error {
+	if charset == types.DoubleByteNone {
+		e.doubleByte = nil
+		return nil
+	}
+	dbcs, ok := doubleByteCharsets[charset]
+	if !ok {
+		return fmt.Errorf("unsupported double-byte charset: %s", charset)
+	}
+	e.doubleByte = dbcs
+	return nil
+}
+
+// SetDoubleByteMode records whether the display is in double-byte (Kanji) mode.
+func (e *CharsetEncoder) SetDoubleByteMode(enabled bool) {
+	e.doubleByteMode = enabled
+}
+
+// isWide reports whether r is a full-width (East Asian wide) character that
+// needs Kanji mode and occupies two display columns.
+func isWide(r rune) bool {
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return true
+	}
+	return false
+}
+
+// isASCII reports whether text contains only 7-bit characters, which read the
+// same in every code page and in Kanji mode.
+func isASCII(text string) bool {
+	for i := 0; i < len(text); i++ {
+		if text[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// encodeDoubleByte encodes text in Kanji mode, entering it on the display
+// first if needed. ASCII stays single-byte; each full-width character becomes
+// two bytes and therefore occupies two columns. Characters the variant cannot
+// represent are shown as their ASCII approximation or replaced with '?' (and
+// noted in report, if not nil).
+func (e *CharsetEncoder) encodeDoubleByte(text string, display types.CharsetSwitcher, report *types.Measurement) ([]byte, error) {
+	enc := e.doubleByte.encoding.NewEncoder()
+	result := make([]byte, 0, len(text))
+	for offset, r := range text {
+		if r < utf8.RuneSelf {
+			result = append(result, byte(r))
+			continue
+		}
+		encoded, err := enc.String(string(r))
+		if err != nil || (e.doubleByte.valid != nil && !e.doubleByte.valid(encoded)) {
+			if approximation, ok := e.Approximation(r); ok && isASCII(approximation) {
+				result = append(result, approximation...)
+				if report != nil {
+					report.Substitutions = append(report.Substitutions, types.Substitution{
+						Offset: offset, Rune: r, Replacement: approximation,
+					})
+				}
+				continue
+			}
+			replacement := strings.Repeat("?", runeCells(r))
+			result = append(result, replacement...)
+			if report != nil {
+				report.Substitutions = append(report.Substitutions, types.Substitution{
+					Offset: offset, Rune: r, Replacement: replacement, Replaced: true,
+				})
+			}
+			continue
+		}
+		result = append(result, encoded...)
+	}
+
+	if !e.doubleByteMode {
+		if err := display.SetDoubleByteMode(true); err != nil {
+			return nil, fmt.Errorf("double-byte mode switch failed: %w", err)
+		}
+		e.doubleByteMode = true
+	}
+	if e.international.replacesAny(asciiBytes(text)) {
+		if err := e.switchInternational(&internationalCharsets[0], display); err != nil {
+			return nil, err
+		}
+	}
+	if report != nil {
+		report.DoubleByte = true
+	}
+	return result, nil
+}
+
+// runeCells returns the number of display columns r occupies.
+func runeCells(r rune) int {
+	if isWide(r) {
+		return 2
+	}
+	return 1
+}
+
+// asciiBytes returns the single-byte ASCII characters of text.
+func asciiBytes(text string) []byte {
+	result := make([]byte, 0, len(text))
+	for i := 0; i < len(text); i++ {
+		if text[i] < utf8.RuneSelf {
+			result = append(result, text[i])
+		}
+	}
+	return result
+}
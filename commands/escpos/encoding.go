@@ -1,98 +1,510 @@
 package escpos
 
 import (
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
 	"unicode/utf8"
 
-	"golang.org/x/text/encoding"
+	"github.com/corrreia/govfd/types"
+
 	"golang.org/x/text/encoding/charmap"
 )
 
 // CharsetEncoder handles character encoding conversion from UTF-8 to legacy charsets.
-// Supports auto-detection for Latin characters (Portuguese, Spanish, French, German, Italian).
+// Supports auto-detection for Latin characters (Portuguese, Spanish, French, German, Italian)
+// and Japanese half-width Katakana.
 type CharsetEncoder struct {
-	currentCharset int
-	encoder        *encoding.Encoder
+	currentCharset int                     // device page number, -1 if unknown
+	table          *runeTable              // table of the current page (nil if unknown)
+	codePages      types.CodePageTable     // code tables the attached model supports
+	available      pageSet                 // the same tables as a set
+	byPage         []types.CodePage        // the same tables by page number
+	doubleByte     *doubleByteCharset      // Kanji mode charset of Asian variants (nil if none)
+	doubleByteMode bool                    // whether the display is in Kanji mode
+	glyphs         glyphCache              // user-defined glyphs and their slots
+	international  *internationalCharset   // active ESC R character set
+	fixedIntl      bool                    // the display cannot select international sets
+	invalidUTF8    types.InvalidUTF8Policy // how to treat text that is not valid UTF-8
+	policy         types.CharsetPolicy     // how code tables are selected
+	approximations map[rune]string         // user stand-ins for unshowable symbols
 }
 
-// NewCharsetEncoder creates a new character encoder with default charset (PC437).
+// NewCharsetEncoder creates a new character encoder with default charset (PC437)
+// and the standard ESC/POS code table numbering.
 func NewCharsetEncoder() *CharsetEncoder {
 	e := &CharsetEncoder{
 		currentCharset: chartablePC437,
+		international:  &internationalCharsets[0],
 	}
-	e.updateEncoder()
+	e.setCodePages(defaultCodePages)
+	e.updateTable()
 	return e
 }
 
-// SetCharset sets the current character encoding table.
+// SetCodePages restricts the encoder to the code tables a model supports,
+// using the model's own page numbers, and resets it to the power-on state.
+// Each table needs a page of its own; the encoder keeps a copy of pages.
+func (e *CharsetEncoder) SetCodePages(pages types.CodePageTable) error {
+	if err := validateCodePages(pages); err != nil {
+		return err
+	}
+	e.setCodePages(maps.Clone(pages))
+	e.Reset()
+	return nil
+}
+
+// validateCodePages checks that pages lists known code tables on distinct
+// page numbers 0..255.
+func validateCodePages(pages types.CodePageTable) error {
+	if len(pages) == 0 {
+		return errors.New("code page table is empty")
+	}
+	used := make(map[int]types.CodePage, len(pages))
+	for _, codePage := range slices.Sorted(maps.Keys(pages)) {
+		page := pages[codePage]
+		if page < 0 || page > 255 {
+			return fmt.Errorf("code page %s: page %d out of range 0..255", codePage, page)
+		}
+		if tableFor(codePage) == nil {
+			return fmt.Errorf("unsupported code page: %s", codePage)
+		}
+		if other, ok := used[page]; ok {
+			return fmt.Errorf("code pages %s and %s both use page %d", other, codePage, page)
+		}
+		used[page] = codePage
+	}
+	return nil
+}
+
+// setCodePages records the model's code tables and the lookups derived
+// from them.
+func (e *CharsetEncoder) setCodePages(pages types.CodePageTable) {
+	e.codePages = pages
+	e.available = pagesOf(pages)
+	e.byPage = make([]types.CodePage, 0, len(pages))
+	for codePage := range pages {
+		e.byPage = append(e.byPage, codePage)
+	}
+	sort.Slice(e.byPage, func(i, j int) bool {
+		a, b := e.byPage[i], e.byPage[j]
+		if pages[a] != pages[b] {
+			return pages[a] < pages[b]
+		}
+		return a < b
+	})
+}
+
+// SetCharset sets the current character encoding table (device page number).
 func (e *CharsetEncoder) SetCharset(charset int) {
 	e.currentCharset = charset
-	e.updateEncoder()
+	e.updateTable()
 }
 
-// updateEncoder updates the internal encoder based on current charset.
-func (e *CharsetEncoder) updateEncoder() {
-	e.encoder = encoderForCharset(e.currentCharset)
+// SetInternationalSwitching sets whether the encoder may select international
+// character sets (ESC R). Displays without them stay on the USA set.
+func (e *CharsetEncoder) SetInternationalSwitching(enabled bool) {
+	e.fixedIntl = !enabled
+	if e.fixedIntl {
+		e.international = &internationalCharsets[0]
+	}
+}
+
+// Reset restores the power-on state (PC437, USA international set, Kanji mode
+// off, no downloaded glyphs), matching what the display does on initialization. The double-byte
+// charset and registered glyphs are kept.
+// If the model has no PC437 table its only table is active, or, if it has
+// several, the active page is treated as unknown.
+func (e *CharsetEncoder) Reset() {
+	e.doubleByteMode = false
+	e.international = &internationalCharsets[0]
+	e.glyphs.reset()
+	e.SetCharset(powerOnPage(e.codePages))
+}
+
+// powerOnPage returns the page a display with the code tables pages shows
+// after initialization: PC437's, else its only table's, else -1 (unknown).
+func powerOnPage(pages types.CodePageTable) int {
+	if page, ok := pages[types.CodePagePC437]; ok {
+		return page
+	}
+	if len(pages) == 1 {
+		for _, page := range pages {
+			return page
+		}
+	}
+	return -1
 }
 
-// encoderForCharset creates a fresh encoder for the given charset page
-// without mutating any state.
-func encoderForCharset(charset int) *encoding.Encoder {
-	switch charset {
-	case chartablePC850:
-		return charmap.CodePage850.NewEncoder()
-	case chartablePC860:
-		return charmap.CodePage860.NewEncoder()
-	case chartablePC858:
-		return charmap.CodePage858.NewEncoder()
-	default:
-		return charmap.CodePage437.NewEncoder()
+// updateTable selects the rune table of the current charset.
+func (e *CharsetEncoder) updateTable() {
+	e.table = nil
+	if codePage, ok := e.codePageFor(e.currentCharset); ok {
+		e.table = tableFor(codePage)
 	}
 }
 
-// CharsetSwitcher defines the interface for charset switching on the display.
-type CharsetSwitcher interface {
-	SetCharacterCodeTableInternal(page int) error
+// codePageFor returns which code table the model selects with device page.
+func (e *CharsetEncoder) codePageFor(page int) (types.CodePage, bool) {
+	for _, codePage := range e.byPage {
+		if e.codePages[codePage] == page {
+			return codePage, true
+		}
+	}
+	return "", false
+}
+
+// latinCodePages maps the Latin code tables to their x/text charmaps.
+var latinCodePages = map[types.CodePage]*charmap.Charmap{
+	types.CodePagePC437: charmap.CodePage437,
+	types.CodePagePC850: charmap.CodePage850,
+	types.CodePagePC860: charmap.CodePage860,
+	types.CodePagePC858: charmap.CodePage858,
+}
+
+// encodeRuneInCodePage returns the byte for r in the given code table.
+func encodeRuneInCodePage(codePage types.CodePage, r rune) (byte, bool) {
+	t := tableFor(codePage)
+	if t == nil {
+		return 0, false
+	}
+	return t.encodeRune(r)
 }
 
 // EncodeTextWithAutoCharsetSwitching encodes UTF-8 text for a VFD display,
 // automatically detecting the best charset and switching hardware if needed.
 //
 // The function tries the current charset first, then auto-detects a better one.
+// On Asian variants, text containing full-width characters is encoded in
+// double-byte (Kanji) mode, which is entered and left automatically.
+// Otherwise full-width Katakana is folded to half-width before encoding.
+// Symbols such as '™' or '→' that no supported code table has are shown as
+// an ASCII approximation ("TM", "->"); see SetApproximation.
+// A different international character set (ESC R) is preferred over a code
+// table switch when it covers the text, and is reset to USA whenever the text
+// needs one of the ASCII characters it replaces.
+// Runes no supported code table has are drawn from user-defined glyphs
+// registered with DefineGlyph, which are downloaded on demand.
 // Unrepresentable characters are replaced with '?' rather than sending raw UTF-8
-// bytes that the display firmware cannot interpret.
-func (e *CharsetEncoder) EncodeTextWithAutoCharsetSwitching(text string, display CharsetSwitcher) ([]byte, error) {
+// bytes that the display firmware cannot interpret. Invalid UTF-8 is handled
+// according to SetInvalidUTF8Policy and never sent as raw bytes.
+func (e *CharsetEncoder) EncodeTextWithAutoCharsetSwitching(text string, display types.CharsetSwitcher) ([]byte, error) {
+	return e.encode(text, display, nil)
+}
+
+// EncodeTextWithReport encodes text exactly like EncodeTextWithAutoCharsetSwitching
+// and also describes the result: the columns it occupies, the character sets
+// it needs and the runes that are transliterated or replaced.
+// Report.Switches is left for the caller, which knows the command bytes.
+func (e *CharsetEncoder) EncodeTextWithReport(text string, display types.CharsetSwitcher) ([]byte, *types.Measurement, error) {
+	report := &types.Measurement{}
+	encoded, err := e.encode(text, display, report)
+	if err != nil {
+		return nil, nil, err
+	}
+	report.Cells = len(encoded)
+	report.International = e.international.id
+	return encoded, report, nil
+}
+
+// Encode encodes text like EncodeTextWithAutoCharsetSwitching and also returns
+// the number of display cells it occupies, which is one per byte.
+func (e *CharsetEncoder) Encode(text string, display types.CharsetSwitcher) ([]byte, int, error) {
+	encoded, err := e.encode(text, display, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	return encoded, len(encoded), nil
+}
+
+// Measure describes how Encode would treat text without changing the
+// encoder's state. The commands Encode would send go to display.
+func (e *CharsetEncoder) Measure(text string, display types.CharsetSwitcher) (*types.Measurement, error) {
+	_, report, err := e.Clone().EncodeTextWithReport(text, display)
+	return report, err
+}
+
+// encode implements EncodeTextWithAutoCharsetSwitching, noting what happens
+// to the text in report if it is not nil.
+func (e *CharsetEncoder) encode(text string, display types.CharsetSwitcher, report *types.Measurement) ([]byte, error) {
 	if !utf8.ValidString(text) {
-		return []byte(text), nil
+		valid, replaced, origins, err := e.makeValidUTF8(text, report != nil)
+		if err != nil {
+			return nil, err
+		}
+		encoded, err := e.encode(valid, display, report)
+		if err == nil && report != nil {
+			// Report offsets in the caller's text, not the repaired one.
+			for i := range report.Substitutions {
+				report.Substitutions[i].Offset = origins[report.Substitutions[i].Offset]
+			}
+			for _, offset := range replaced {
+				report.Substitutions = append(report.Substitutions, types.Substitution{
+					Offset: offset, Rune: utf8.RuneError, Replacement: "?", Replaced: true,
+				})
+			}
+			sort.SliceStable(report.Substitutions, func(i, j int) bool {
+				return report.Substitutions[i].Offset < report.Substitutions[j].Offset
+			})
+		}
+		return encoded, err
+	}
+	if e.doubleByte != nil && strings.ContainsFunc(text, isWide) {
+		return e.encodeDoubleByte(text, display, report)
+	}
+	// Bytes above 0x7F would be read as lead bytes in Kanji mode.
+	if e.doubleByteMode && !isASCII(text) {
+		if err := display.SetDoubleByteMode(false); err != nil {
+			return nil, fmt.Errorf("double-byte mode switch failed: %w", err)
+		}
+		e.doubleByteMode = false
 	}
+	folded := e.fold(text)
 
-	// Try current charset first.
-	if e.encoder != nil {
-		if encoded, err := e.encoder.String(text); err == nil {
-			return []byte(encoded), nil
+	glyphed := e.glyphRunes(folded)
+	pageText := folded
+	if glyphed != nil {
+		// Glyph runes get slot codes later; any page can carry a placeholder.
+		pageText = strings.Map(func(r rune) rune {
+			if glyphed[r] {
+				return ' '
+			}
+			return r
+		}, folded)
+	}
+	encoded, err := e.encodeSingleByte(pageText, display)
+	if err != nil {
+		return nil, err
+	}
+	if glyphed != nil || len(e.glyphs.occupant) > 0 {
+		if err := e.placeGlyphs(folded, encoded, glyphed, display); err != nil {
+			return nil, err
 		}
 	}
+	if report != nil {
+		e.describeSingleByte(text, encoded, glyphed, report)
+	}
+	return encoded, nil
+}
 
-	// Auto-detect best charset for this text.
-	bestCharset := e.detectBestCharset(text)
-	if bestCharset == e.currentCharset {
-		// Already using the best candidate but it failed above.
-		return SanitizeForDisplay(text), nil
+// describeSingleByte fills report for text encoded one byte per folded rune:
+// which runes were folded, approximated or replaced, and whether the code table is needed.
+func (e *CharsetEncoder) describeSingleByte(text string, encoded []byte, glyphed map[rune]bool, report *types.Measurement) {
+	needsPage := false
+	i := 0
+	for offset, r := range text {
+		shown, folded := e.foldRune(r)
+		if !folded {
+			shown = string(r)
+		}
+		replaced := false
+		for _, f := range shown {
+			b := encoded[i]
+			i++
+			switch {
+			case b == '?' && f != '?':
+				replaced = true
+			case f >= utf8.RuneSelf && b >= utf8.RuneSelf && !glyphed[f]:
+				needsPage = true
+			}
+		}
+		if replaced {
+			report.Substitutions = append(report.Substitutions, types.Substitution{
+				Offset: offset, Rune: r, Replacement: "?", Replaced: true,
+			})
+		} else if folded {
+			report.Substitutions = append(report.Substitutions, types.Substitution{
+				Offset: offset, Rune: r, Replacement: shown,
+			})
+		}
 	}
+	if codePage, ok := e.codePageFor(e.currentCharset); ok && needsPage {
+		report.CodePages = []types.CodePage{codePage}
+	}
+}
 
-	// Try encoding with the candidate charset without mutating encoder state.
-	testEnc := encoderForCharset(bestCharset)
-	encoded, err := testEnc.String(text)
-	if err != nil {
+// Clone returns an independent copy of the encoder and its tracked state,
+// for trying out an encoding without affecting the original.
+func (e *CharsetEncoder) Clone() *CharsetEncoder {
+	c := *e
+	c.glyphs.glyphs = make(map[rune]types.Glyph, len(e.glyphs.glyphs))
+	for r, glyph := range e.glyphs.glyphs {
+		c.glyphs.glyphs[r] = glyph
+	}
+	c.glyphs.occupant = make(map[byte]rune, len(e.glyphs.occupant))
+	for code, r := range e.glyphs.occupant {
+		c.glyphs.occupant[code] = r
+	}
+	c.glyphs.lastUsed = make(map[byte]uint64, len(e.glyphs.lastUsed))
+	for code, used := range e.glyphs.lastUsed {
+		c.glyphs.lastUsed[code] = used
+	}
+	c.updateTable()
+	return &c
+}
+
+// encodeSingleByte encodes text in one single-byte code table, one byte per
+// rune. If the current table fails, it first tries another international
+// character set on the same table, then switches to a better supported table,
+// following the charset policy. If no table covers the text, the one showing
+// most of it is used and the rest is replaced with '?'.
+func (e *CharsetEncoder) encodeSingleByte(text string, display types.CharsetSwitcher) ([]byte, error) {
+	if e.table != nil && e.keepsCurrent(e.table.codePage, text) {
+		current := e.table.codePage
+		// Try current charset first.
+		if encoded, ok := e.encodeIn(current, e.international, text); ok {
+			return encoded, nil
+		}
+		// An international set may cover the text without a code table switch.
+		// It must at least show the first rune the table lacks.
+		missing, lacks := e.table.firstMissing(text)
+		for i := range internationalCharsets {
+			if e.fixedIntl {
+				break
+			}
+			intl := &internationalCharsets[i]
+			if intl == e.international {
+				continue
+			}
+			if _, ok := intl.encodeRune(missing); lacks && !ok {
+				continue
+			}
+			if encoded, ok := e.encodeIn(current, intl, text); ok {
+				if err := e.switchInternational(intl, display); err != nil {
+					return nil, err
+				}
+				return encoded, nil
+			}
+		}
+	}
+	// Try the supported code tables best-first.
+	candidates := e.candidates(text)
+	for _, codePage := range candidates.slice() {
+		for _, intl := range [...]*internationalCharset{e.international, &internationalCharsets[0]} {
+			// Try encoding with the candidate charset without mutating encoder state.
+			encoded, ok := e.encodeIn(codePage, intl, text)
+			if !ok {
+				continue
+			}
+			// Encoding succeeded — switch hardware and encoder state atomically.
+			if err := e.switchCodePage(codePage, display); err != nil {
+				return nil, err
+			}
+			if err := e.switchInternational(intl, display); err != nil {
+				return nil, err
+			}
+			return encoded, nil
+		}
+	}
+
+	// No table covers all of text: count what each one lacks.
+	fallback := e.fallbackCodePages(candidates)
+	if fallback.n == 0 {
 		return SanitizeForDisplay(text), nil
 	}
+	var bits [len(codePageOrder)]pageSet
+	for i, codePage := range fallback.slice() {
+		bits[i] = tableFor(codePage).bit
+	}
+	var missing [len(codePageOrder)]int
+	for _, r := range text {
+		has := pagesWith(r)
+		for i := range fallback.slice() {
+			if has&bits[i] == 0 {
+				missing[i]++
+			}
+		}
+	}
+	fewest := 0
+	for i := range fallback.slice() {
+		if missing[i] < missing[fewest] {
+			fewest = i
+		}
+	}
+	bestCodePage := fallback.pages[fewest]
+	best := encodePartial(bestCodePage, text)
+	if err := e.switchCodePage(bestCodePage, display); err != nil {
+		return nil, err
+	}
+	// '#', '$' and friends must not come out as another country's characters.
+	if e.international.replacesAny(best) {
+		if err := e.switchInternational(&internationalCharsets[0], display); err != nil {
+			return nil, err
+		}
+	}
+	return best, nil
+}
 
-	// Encoding succeeded — switch hardware and encoder state atomically.
-	if err := display.SetCharacterCodeTableInternal(bestCharset); err != nil {
-		return nil, fmt.Errorf("charset switch failed: %w", err)
+// switchCodePage selects codePage on the display unless it is already active.
+func (e *CharsetEncoder) switchCodePage(codePage types.CodePage, display types.CharsetSwitcher) error {
+	page := e.codePages[codePage]
+	if page == e.currentCharset {
+		return nil
+	}
+	if err := display.SelectCodeTable(page); err != nil {
+		return fmt.Errorf("charset switch failed: %w", err)
 	}
+	e.SetCharset(page)
+	return nil
+}
 
-	return []byte(encoded), nil
+// encodeIn encodes text in codePage while the international set intl is
+// active, one byte per rune. It reports false if any rune is unrepresentable.
+func (e *CharsetEncoder) encodeIn(codePage types.CodePage, intl *internationalCharset, text string) ([]byte, bool) {
+	t := e.table
+	if t == nil || t.codePage != codePage {
+		t = tableFor(codePage)
+	}
+	if t == nil {
+		return nil, false
+	}
+	if intl.id == types.InternationalUSA {
+		if pagesCovering(text)&t.bit == 0 {
+			return nil, false
+		}
+	} else {
+		for _, r := range text {
+			if _, ok := encodeRuneWithInternational(t, intl, r); !ok {
+				return nil, false
+			}
+		}
+	}
+	result := make([]byte, 0, utf8.RuneCountInString(text))
+	for _, r := range text {
+		var b byte
+		if intl.id == types.InternationalUSA {
+			b, _ = t.encodeRune(r)
+		} else {
+			b, _ = encodeRuneWithInternational(t, intl, r)
+		}
+		result = append(result, b)
+	}
+	return result, true
+}
+
+// switchInternational selects intl on the display unless it is already active.
+func (e *CharsetEncoder) switchInternational(intl *internationalCharset, display types.CharsetSwitcher) error {
+	if intl == e.international {
+		return nil
+	}
+	if err := display.SelectInternationalCharset(intl.id); err != nil {
+		return fmt.Errorf("international charset switch failed: %w", err)
+	}
+	e.international = intl
+	return nil
+}
+
+// SetInternationalCharset records the active international character set.
+func (e *CharsetEncoder) SetInternationalCharset(charset types.InternationalCharset) error {
+	intl, ok := lookupInternationalCharset(charset)
+	if !ok {
+		return fmt.Errorf("unsupported international charset: %s", charset)
+	}
+	e.international = intl
+	return nil
 }
 
 // SanitizeForDisplay replaces any non-ASCII rune with '?' so that only
@@ -110,17 +522,22 @@ func SanitizeForDisplay(text string) []byte {
 	return result
 }
 
-// detectBestCharset analyzes Latin text and returns the best charset to use.
-func (e *CharsetEncoder) detectBestCharset(text string) int {
+// candidateCodePages returns the code tables likely to represent text,
+// best first. Later entries are fallbacks for models lacking earlier ones.
+// The result is shared and must not be modified.
+func candidateCodePages(text string) []types.CodePage {
 	hasPortuguese := false
 	hasEuro := false
 	hasLatin := false
+	hasKatakana := false
 
 	for _, r := range text {
 		if r > 127 {
 			switch {
 			case r == '€':
 				hasEuro = true
+			case isHalfWidthKatakana(r):
+				hasKatakana = true
 			case r >= 'À' && r <= 'ÿ':
 				hasLatin = true
 				if r == 'ã' || r == 'õ' || r == 'ç' || r == 'Ã' || r == 'Õ' || r == 'Ç' {
@@ -130,15 +547,25 @@ func (e *CharsetEncoder) detectBestCharset(text string) int {
 		}
 	}
 
-	if hasPortuguese {
-		return chartablePC860
-	}
-	if hasEuro {
-		return chartablePC858
-	}
-	if hasLatin {
-		return chartablePC850
+	switch {
+	case hasKatakana:
+		return katakanaCandidates
+	case hasPortuguese:
+		return portugueseCandidates
+	case hasEuro:
+		return euroCandidates
+	case hasLatin:
+		return latinCandidates
 	}
-
-	return chartablePC437
+	return asciiCandidates
 }
+
+// Candidate lists returned by candidateCodePages. They are shared and must
+// not be modified.
+var (
+	katakanaCandidates   = []types.CodePage{types.CodePageKatakana, types.CodePageHD44780}
+	portugueseCandidates = []types.CodePage{types.CodePagePC860, types.CodePagePC850, types.CodePagePC858}
+	euroCandidates       = []types.CodePage{types.CodePagePC858}
+	latinCandidates      = []types.CodePage{types.CodePagePC850, types.CodePagePC858, types.CodePagePC860, types.CodePagePC437}
+	asciiCandidates      = []types.CodePage{types.CodePagePC437}
+)
@@ -3,31 +3,54 @@ package escpos
 import (
 	"errors"
 	"testing"
+
+	"github.com/corrreia/govfd/types"
 )
 
-// mockDisplay implements CharsetSwitcher for testing.
-// It mirrors what Display.SetCharacterCodeTableInternal does:
-// on success, it updates both hardware page and encoder state.
+// mockDisplay implements types.CharsetSwitcher for testing, recording the
+// commands the encoder asks for.
 type mockDisplay struct {
-	encoder     *CharsetEncoder // linked encoder to update on success
 	currentPage int
 	failOnPage  int // return error when switching to this page (-1 = never fail)
 	switchCount int
+	kanjiMode   bool
+	kanjiCount  int                          // number of Kanji mode enter/leave commands
+	downloads   []byte                       // slot codes glyphs were downloaded to, in order
+	cancels     []byte                       // slot codes cancelled, in order
+	intl        []types.InternationalCharset // international sets selected, in order
 }
 
 func newMockDisplay() *mockDisplay {
 	return &mockDisplay{failOnPage: -1}
 }
 
-func (m *mockDisplay) SetCharacterCodeTableInternal(page int) error {
+func (m *mockDisplay) SelectCodeTable(page int) error {
 	m.switchCount++
 	if page == m.failOnPage {
 		return errors.New("hardware switch failed")
 	}
 	m.currentPage = page
-	if m.encoder != nil {
-		m.encoder.SetCharset(page)
-	}
+	return nil
+}
+
+func (m *mockDisplay) SetDoubleByteMode(enabled bool) error {
+	m.kanjiCount++
+	m.kanjiMode = enabled
+	return nil
+}
+
+func (m *mockDisplay) DefineGlyph(code byte, glyph types.Glyph) error {
+	m.downloads = append(m.downloads, code)
+	return nil
+}
+
+func (m *mockDisplay) CancelGlyph(code byte) error {
+	m.cancels = append(m.cancels, code)
+	return nil
+}
+
+func (m *mockDisplay) SelectInternationalCharset(charset types.InternationalCharset) error {
+	m.intl = append(m.intl, charset)
 	return nil
 }
 
@@ -39,8 +62,8 @@ func TestNewCharsetEncoder(t *testing.T) {
 	if enc.currentCharset != chartablePC437 {
 		t.Errorf("default charset = %d, want %d (PC437)", enc.currentCharset, chartablePC437)
 	}
-	if enc.encoder == nil {
-		t.Fatal("encoder is nil after init")
+	if enc.table == nil {
+		t.Fatal("rune table is nil after init")
 	}
 }
 
@@ -111,7 +134,7 @@ func TestEncodeEuro(t *testing.T) {
 func TestNoHardwareSwitchWhenCurrentCharsetWorks(t *testing.T) {
 	enc := NewCharsetEncoder()
 	display := newMockDisplay()
-	display.encoder = enc // link so SetCharacterCodeTableInternal updates encoder
+	// The encoder records the new page once the display accepts the switch.
 
 	// First call switches to PC860 for Portuguese
 	enc.EncodeTextWithAutoCharsetSwitching("ação", display)
@@ -199,26 +222,34 @@ func TestSanitizeFallbackForUnrepresentableChars(t *testing.T) {
 	}
 }
 
-func TestDetectBestCharset(t *testing.T) {
-	enc := NewCharsetEncoder()
-
+func TestCandidateCodePages(t *testing.T) {
 	tests := []struct {
 		text string
-		want int
+		want types.CodePage
 	}{
-		{"Hello", chartablePC437},
-		{"café", chartablePC850},  // detected as Latin (even though PC437 handles it)
-		{"ação", chartablePC860},  // Portuguese-specific
-		{"€100", chartablePC858},  // Euro
-		{"café ação", chartablePC860}, // Portuguese takes priority
+		{"Hello", types.CodePagePC437},
+		{"café", types.CodePagePC850},      // detected as Latin (even though PC437 handles it)
+		{"ação", types.CodePagePC860},      // Portuguese-specific
+		{"€100", types.CodePagePC858},      // Euro
+		{"café ação", types.CodePagePC860}, // Portuguese takes priority
+		{"ｺｰﾋｰ", types.CodePageKatakana},   // half-width Katakana
 	}
 
 	for _, tt := range tests {
-		got := enc.detectBestCharset(tt.text)
-		if got != tt.want {
-			t.Errorf("detectBestCharset(%q) = %d, want %d", tt.text, got, tt.want)
+		if got := candidateCodePages(tt.text); got[0] != tt.want {
+			t.Errorf("candidateCodePages(%q) = %v, want %s first", tt.text, got, tt.want)
 		}
 	}
+
+	// The encoder switches to the best candidate the model supports
+	enc := NewCharsetEncoder()
+	display := newMockDisplay()
+	if _, err := enc.EncodeTextWithAutoCharsetSwitching("€100", display); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if display.currentPage != chartablePC858 {
+		t.Errorf("page = %d after encoding €, want PC858 (%d)", display.currentPage, chartablePC858)
+	}
 }
 
 func TestEncodedOutputIsSingleBytePerChar(t *testing.T) {
@@ -233,7 +264,7 @@ func TestEncodedOutputIsSingleBytePerChar(t *testing.T) {
 		{"café", 4},   // 4 display chars (é is single byte in CP437)
 		{"ação", 4},   // 4 display chars after switch to PC860
 		{"€19.99", 6}, // 6 display chars after switch to PC858
-		{"日本語", 3},   // 3 sanitized '?' chars
+		{"日本語", 3},    // 3 sanitized '?' chars
 	}
 
 	for _, tt := range tests {
@@ -250,3 +281,232 @@ func TestEncodedOutputIsSingleBytePerChar(t *testing.T) {
 		}
 	}
 }
+
+func TestEncodeHalfWidthKatakana(t *testing.T) {
+	enc := NewCharsetEncoder()
+	display := newMockDisplay()
+
+	// ｺｰﾋｰ is not in PC437 — requires switch to the Katakana page
+	result, err := enc.EncodeTextWithAutoCharsetSwitching("ｺｰﾋｰ 350", display)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []byte{0xBA, 0xB0, 0xCB, 0xB0, ' ', '3', '5', '0'}
+	if string(result) != string(want) {
+		t.Errorf("got % X, want % X", result, want)
+	}
+	if display.currentPage != chartableKatakana {
+		t.Errorf("hardware page = %d, want %d (Katakana)", display.currentPage, chartableKatakana)
+	}
+}
+
+func TestEncodeFullWidthKatakanaFolded(t *testing.T) {
+	enc := NewCharsetEncoder()
+	display := newMockDisplay()
+
+	// ガ has no single half-width form: it folds to ｶ plus the dakuten mark ﾞ
+	result, err := enc.EncodeTextWithAutoCharsetSwitching("ガム。", display)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []byte{0xB6, 0xDE, 0xD1, 0xA1}
+	if string(result) != string(want) {
+		t.Errorf("got % X, want % X", result, want)
+	}
+	if display.currentPage != chartableKatakana {
+		t.Errorf("hardware page = %d, want %d (Katakana)", display.currentPage, chartableKatakana)
+	}
+}
+
+func TestFoldKatakana(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"カタカナ", "ｶﾀｶﾅ"},
+		{"パン", "ﾊﾟﾝ"},
+		{"「コーヒー」", "｢ｺｰﾋｰ｣"},
+		{"ヴ", "ｳﾞ"},
+		{"ヰ", "ヰ"}, // no half-width form
+		{"café", "café"},
+		{"ｶﾀｶﾅ", "ｶﾀｶﾅ"},
+	}
+
+	for _, tt := range tests {
+		if got := foldKatakana(tt.input); got != tt.want {
+			t.Errorf("foldKatakana(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestEncodeDoubleByteEntersKanjiMode(t *testing.T) {
+	enc := NewCharsetEncoder()
+	if err := enc.SetDoubleByteCharset(types.DoubleByteGB2312); err != nil {
+		t.Fatalf("SetDoubleByteCharset: %v", err)
+	}
+	display := newMockDisplay()
+
+	result, err := enc.EncodeTextWithAutoCharsetSwitching("中文 OK", display)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []byte{0xD6, 0xD0, 0xCE, 0xC4, ' ', 'O', 'K'}
+	if string(result) != string(want) {
+		t.Errorf("got % X, want % X", result, want)
+	}
+	if !display.kanjiMode || display.kanjiCount != 1 {
+		t.Errorf("kanji mode = %v after %d commands, want on after 1", display.kanjiMode, display.kanjiCount)
+	}
+
+	// Still in Kanji mode — no second FS &
+	enc.EncodeTextWithAutoCharsetSwitching("你好", display)
+	if display.kanjiCount != 1 {
+		t.Errorf("kanji command count = %d, want 1 (already in Kanji mode)", display.kanjiCount)
+	}
+
+	// ASCII reads the same in Kanji mode — stay there
+	enc.EncodeTextWithAutoCharsetSwitching("Total", display)
+	if !display.kanjiMode {
+		t.Error("left Kanji mode for ASCII-only text")
+	}
+
+	// Single-byte accented text must leave Kanji mode first
+	enc.EncodeTextWithAutoCharsetSwitching("ação", display)
+	if display.kanjiMode {
+		t.Error("still in Kanji mode after single-byte Latin text")
+	}
+	if display.currentPage != chartablePC860 {
+		t.Errorf("hardware page = %d, want %d (PC860)", display.currentPage, chartablePC860)
+	}
+}
+
+func TestEncodeDoubleByteVariants(t *testing.T) {
+	tests := []struct {
+		charset types.DoubleByteCharset
+		text    string
+		want    []byte
+	}{
+		{types.DoubleByteBig5, "中文", []byte{0xA4, 0xA4, 0xA4, 0xE5}},
+		{types.DoubleByteKSC5601, "한글", []byte{0xC7, 0xD1, 0xB1, 0xDB}},
+		{types.DoubleByteShiftJIS, "カナ", []byte{0x83, 0x4A, 0x83, 0x69}}, // not folded to half-width
+		{types.DoubleByteGB2312, "中丂", []byte{0xD6, 0xD0, '?', '?'}},     // GBK-only character
+	}
+
+	for _, tt := range tests {
+		enc := NewCharsetEncoder()
+		enc.SetDoubleByteCharset(tt.charset)
+
+		result, err := enc.EncodeTextWithAutoCharsetSwitching(tt.text, newMockDisplay())
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.charset, err)
+		}
+		if string(result) != string(tt.want) {
+			t.Errorf("%s: EncodeText(%q) = % X, want % X", tt.charset, tt.text, result, tt.want)
+		}
+	}
+}
+
+func TestSetDoubleByteCharsetUnknown(t *testing.T) {
+	enc := NewCharsetEncoder()
+	if err := enc.SetDoubleByteCharset("EBCDIC"); err == nil {
+		t.Fatal("expected error for unknown double-byte charset, got nil")
+	}
+}
+
+func TestEncodeUsesModelCodePageNumbers(t *testing.T) {
+	enc := NewCharsetEncoder()
+	err := enc.SetCodePages(types.CodePageTable{
+		types.CodePagePC437: 0,
+		types.CodePagePC858: 5, // numbered differently from Epson's 19
+	})
+	if err != nil {
+		t.Fatalf("SetCodePages: %v", err)
+	}
+	display := newMockDisplay()
+
+	if _, err := enc.EncodeTextWithAutoCharsetSwitching("€19.99", display); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if display.currentPage != 5 {
+		t.Errorf("hardware page = %d, want 5 (model's PC858 number)", display.currentPage)
+	}
+}
+
+func TestEncodeSkipsPagesModelLacks(t *testing.T) {
+	enc := NewCharsetEncoder()
+	enc.SetCodePages(types.CodePageTable{
+		types.CodePagePC437: 0,
+		types.CodePagePC850: 2, // no PC860 on this model
+	})
+	display := newMockDisplay()
+
+	result, err := enc.EncodeTextWithAutoCharsetSwitching("ação", display)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if display.currentPage != chartablePC850 {
+		t.Errorf("hardware page = %d, want %d (PC850 fallback)", display.currentPage, chartablePC850)
+	}
+	want := []byte{'a', 0x87, 0xC6, 'o'} // ç and ã in PC850
+	if string(result) != string(want) {
+		t.Errorf("got % X, want % X", result, want)
+	}
+
+	// Katakana is not available at all — sanitize without switching
+	count := display.switchCount
+	result, _ = enc.EncodeTextWithAutoCharsetSwitching("ｶﾅ", display)
+	if string(result) != "??" || display.switchCount != count {
+		t.Errorf("got %q after %d switches, want \"??\" with no switch", result, display.switchCount-count)
+	}
+}
+
+func TestSetCodePagesRejectsInvalidTables(t *testing.T) {
+	tests := []struct {
+		pages types.CodePageTable
+		desc  string
+	}{
+		{nil, "empty table"},
+		{types.CodePageTable{types.CodePagePC437: 256}, "page out of range"},
+		{types.CodePageTable{"PC999": 4}, "unknown code page"},
+		{types.CodePageTable{types.CodePagePC437: 0, types.CodePagePC850: 0}, "duplicate page"},
+	}
+
+	for _, tt := range tests {
+		if err := NewCharsetEncoder().SetCodePages(tt.pages); err == nil {
+			t.Errorf("SetCodePages [%s]: expected error, got nil", tt.desc)
+		}
+	}
+}
+
+func TestSetCodePagesCopiesTable(t *testing.T) {
+	pages := types.CodePageTable{types.CodePagePC437: 0, types.CodePagePC860: 3}
+	enc := NewCharsetEncoder()
+	if err := enc.SetCodePages(pages); err != nil {
+		t.Fatalf("SetCodePages: %v", err)
+	}
+	pages[types.CodePagePC860] = 0 // The encoder keeps its own copy
+
+	mock := newMockDisplay()
+	if _, err := enc.EncodeTextWithAutoCharsetSwitching("ação", mock); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if mock.currentPage != 3 {
+		t.Errorf("selected page %d, want 3 (PC860 as registered)", mock.currentPage)
+	}
+}
+
+func TestSingleCodePageIsActiveAfterReset(t *testing.T) {
+	enc := NewCharsetEncoder()
+	if err := enc.SetCodePages(types.CodePageTable{types.CodePageKatakana: 0}); err != nil {
+		t.Fatalf("SetCodePages: %v", err)
+	}
+	mock := newMockDisplay()
+
+	encoded, err := enc.EncodeTextWithAutoCharsetSwitching("ｱ", mock)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if string(encoded) != "\xb1" || mock.switchCount != 0 {
+		t.Errorf("got % X after %d switches, want B1 with no switch", encoded, mock.switchCount)
+	}
+}
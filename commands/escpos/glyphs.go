@@ -0,0 +1,234 @@
+package escpos
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/corrreia/govfd/types"
+)
+
+// defaultGlyphSlots are the character codes used for downloaded glyphs on
+// the Latin code tables. They hold box-drawing characters in every supported
+// Latin table, which text rarely needs; when it does, the slot is freed first.
+var defaultGlyphSlots = []byte{0xB0, 0xB1, 0xB2, 0xB3, 0xB4, 0xB9, 0xBA, 0xBB}
+
+// katakanaGlyphSlots are the glyph slots on the Katakana table, where
+// 0xA1-0xDF hold half-width Katakana. 0xE0-0xE7 hold graphic symbols the
+// encoder never produces.
+var katakanaGlyphSlots = []byte{0xE0, 0xE1, 0xE2, 0xE3, 0xE4, 0xE5, 0xE6, 0xE7}
+
+// glyphCache tracks registered user-defined glyphs and which of them are
+// currently downloaded into the display's character slots.
+type glyphCache struct {
+	glyphs   map[rune]types.Glyph
+	slots    []byte          // character codes available for glyphs, if set
+	custom   bool            // slots were set; otherwise they follow the code table
+	occupant map[byte]rune   // slot code -> rune currently downloaded there
+	lastUsed map[byte]uint64 // slot code -> clock value of last use (LRU)
+	clock    uint64
+}
+
+// reset forgets all downloaded glyphs. Registered glyphs are kept.
+func (c *glyphCache) reset() {
+	c.occupant = nil
+	c.lastUsed = nil
+}
+
+// slotOf returns the slot holding r, if it is downloaded.
+func (c *glyphCache) slotOf(r rune) (byte, bool) {
+	for code, occupant := range c.occupant {
+		if occupant == r {
+			return code, true
+		}
+	}
+	return 0, false
+}
+
+// touch marks slot code as just used.
+func (c *glyphCache) touch(code byte) {
+	if c.lastUsed == nil {
+		c.lastUsed = make(map[byte]uint64)
+	}
+	c.clock++
+	c.lastUsed[code] = c.clock
+}
+
+// allocate picks one of slots for a new glyph: a free slot if there is one,
+// otherwise the least recently used slot not listed in reserved.
+func (c *glyphCache) allocate(slots []byte, reserved map[byte]bool) (byte, bool) {
+	var victim byte
+	found := false
+	for _, code := range slots {
+		if reserved[code] {
+			continue
+		}
+		if _, occupied := c.occupant[code]; !occupied {
+			return code, true
+		}
+		if !found || c.lastUsed[code] < c.lastUsed[victim] {
+			victim, found = code, true
+		}
+	}
+	return victim, found
+}
+
+// DefineGlyph registers a user-defined glyph for r. It is downloaded into a
+// free slot the first time text needs r and no supported code table has it.
+// It returns the slot r currently occupies, if any, so the caller can
+// download the new bitmap there.
+func (e *CharsetEncoder) DefineGlyph(r rune, glyph types.Glyph) (byte, bool, error) {
+	for _, row := range glyph {
+		if row >= 1<<types.GlyphWidth {
+			return 0, false, fmt.Errorf("glyph row %#b wider than %d dots", row, types.GlyphWidth)
+		}
+	}
+	if r < 0x20 || r == 0x7F {
+		return 0, false, errors.New("cannot define a glyph for a control character")
+	}
+	if e.glyphs.glyphs == nil {
+		e.glyphs.glyphs = make(map[rune]types.Glyph)
+	}
+	e.glyphs.glyphs[r] = glyph
+	code, ok := e.glyphs.slotOf(r)
+	return code, ok, nil
+}
+
+// Glyph returns the glyph registered for r.
+func (e *CharsetEncoder) Glyph(r rune) (types.Glyph, bool) {
+	glyph, ok := e.glyphs.glyphs[r]
+	return glyph, ok
+}
+
+// SetGlyphSlots sets the character codes that downloaded glyphs may occupy
+// on every code table, instead of slots chosen per table, and forgets which
+// glyphs are downloaded.
+func (e *CharsetEncoder) SetGlyphSlots(codes []byte) error {
+	for _, code := range codes {
+		if code < 0x20 {
+			return fmt.Errorf("glyph slot %#02x is a control code", code)
+		}
+	}
+	e.glyphs.slots = append([]byte(nil), codes...)
+	e.glyphs.custom = true
+	e.glyphs.reset()
+	return nil
+}
+
+// glyphSlots returns the slots new glyphs may occupy on the current code
+// table.
+func (e *CharsetEncoder) glyphSlots() []byte {
+	switch {
+	case e.glyphs.custom:
+		return e.glyphs.slots
+	case e.table != nil && e.table.codePage == types.CodePageKatakana:
+		return katakanaGlyphSlots
+	}
+	return defaultGlyphSlots
+}
+
+// SetGlyphSlot records that the glyph for r now occupies slot code.
+func (e *CharsetEncoder) SetGlyphSlot(code byte, r rune) {
+	if e.glyphs.occupant == nil {
+		e.glyphs.occupant = make(map[byte]rune)
+	}
+	e.glyphs.occupant[code] = r
+	e.glyphs.touch(code)
+}
+
+// ClearGlyphSlot records that slot code holds its built-in character again.
+func (e *CharsetEncoder) ClearGlyphSlot(code byte) {
+	delete(e.glyphs.occupant, code)
+	delete(e.glyphs.lastUsed, code)
+}
+
+// GlyphSlots returns which rune occupies each downloaded glyph slot.
+func (e *CharsetEncoder) GlyphSlots() map[byte]rune {
+	slots := make(map[byte]rune, len(e.glyphs.occupant))
+	for code, r := range e.glyphs.occupant {
+		slots[code] = r
+	}
+	return slots
+}
+
+// glyphRunes returns the runes of text that must be drawn from user-defined
+// glyphs: a glyph is registered and no supported code table has the rune.
+func (e *CharsetEncoder) glyphRunes(text string) map[rune]bool {
+	if len(e.glyphs.glyphs) == 0 {
+		return nil
+	}
+	var result map[rune]bool
+	for _, r := range text {
+		if _, ok := e.glyphs.glyphs[r]; !ok || result[r] || e.anyCodePageHas(r) {
+			continue
+		}
+		if result == nil {
+			result = make(map[rune]bool)
+		}
+		result[r] = true
+	}
+	return result
+}
+
+// anyCodePageHas reports whether any code table the encoder may select has r:
+// the pinned table, or else any table the model supports.
+func (e *CharsetEncoder) anyCodePageHas(r rune) bool {
+	return pagesWith(r)&e.selectable() != 0
+}
+
+// placeGlyphs rewrites the glyph runes of text (one byte per rune in encoded)
+// to their slot codes, downloading glyphs as needed. Slots whose codes the
+// rest of the text needs as built-in characters are freed first. Glyphs that
+// find no slot are replaced with '?'.
+func (e *CharsetEncoder) placeGlyphs(text string, encoded []byte, glyphed map[rune]bool, display types.CharsetSwitcher) error {
+	// Codes the text needs as built-in characters cannot hold glyphs.
+	reserved := make(map[byte]bool)
+	i := 0
+	for _, r := range text {
+		if !glyphed[r] {
+			reserved[encoded[i]] = true
+		}
+		i++
+	}
+	for _, code := range slices.Sorted(maps.Keys(e.glyphs.occupant)) {
+		if reserved[code] {
+			if err := display.CancelGlyph(code); err != nil {
+				return fmt.Errorf("glyph cancel failed: %w", err)
+			}
+			e.ClearGlyphSlot(code)
+		}
+	}
+
+	slotFor := make(map[rune]byte, len(glyphed))
+	for r := range glyphed {
+		if code, ok := e.glyphs.slotOf(r); ok {
+			slotFor[r] = code
+			reserved[code] = true
+		}
+	}
+	i = 0
+	for _, r := range text {
+		if glyphed[r] {
+			code, ok := slotFor[r]
+			if !ok {
+				if code, ok = e.glyphs.allocate(e.glyphSlots(), reserved); ok {
+					if err := display.DefineGlyph(code, e.glyphs.glyphs[r]); err != nil {
+						return fmt.Errorf("glyph download failed: %w", err)
+					}
+					e.SetGlyphSlot(code, r)
+					slotFor[r] = code
+					reserved[code] = true
+				}
+			}
+			if ok {
+				encoded[i] = code
+				e.glyphs.touch(code)
+			} else {
+				encoded[i] = '?'
+			}
+		}
+		i++
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package escpos
+
+import (
+	"testing"
+
+	"github.com/corrreia/govfd/types"
+)
+
+var checkMark = types.Glyph{0b00000, 0b00001, 0b00010, 0b10100, 0b01000, 0b00000, 0b00000}
+
+func TestBuildDefineGlyphSeq(t *testing.T) {
+	seq := BuildDefineGlyphSeq(0xB0, checkMark)
+
+	// Columns left to right, bit 7 = top row
+	want := []byte{
+		CmdEscape, CmdEscDefineGlyph, 1, 0xB0, 0xB0, 5,
+		0x10, 0x08, 0x10, 0x20, 0x40,
+		CmdEscape, CmdEscSelectGlyphs, 1,
+	}
+	if string(seq) != string(want) {
+		t.Errorf("got % X, want % X", seq, want)
+	}
+}
+
+func TestGlyphDownloadedOnFirstUse(t *testing.T) {
+	enc := NewCharsetEncoder()
+	display := newMockDisplay()
+
+	if _, _, err := enc.DefineGlyph('✓', checkMark); err != nil {
+		t.Fatalf("DefineGlyph: %v", err)
+	}
+
+	result, err := enc.EncodeTextWithAutoCharsetSwitching("OK ✓", display)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []byte{'O', 'K', ' ', defaultGlyphSlots[0]}
+	if string(result) != string(want) {
+		t.Errorf("got % X, want % X", result, want)
+	}
+	if len(display.downloads) != 1 {
+		t.Fatalf("downloads = %v, want one", display.downloads)
+	}
+	if slots := enc.GlyphSlots(); slots[defaultGlyphSlots[0]] != '✓' {
+		t.Errorf("glyph slots = %v, want ✓ in %#02x", slots, defaultGlyphSlots[0])
+	}
+
+	// Already downloaded — no second download
+	enc.EncodeTextWithAutoCharsetSwitching("✓✓", display)
+	if len(display.downloads) != 1 {
+		t.Errorf("downloads = %v, want still one", display.downloads)
+	}
+}
+
+func TestGlyphNotUsedWhenCodePageHasRune(t *testing.T) {
+	enc := NewCharsetEncoder()
+	display := newMockDisplay()
+
+	// é exists in PC437, so the registered glyph is never downloaded
+	enc.DefineGlyph('é', checkMark)
+	result, _ := enc.EncodeTextWithAutoCharsetSwitching("é", display)
+	if string(result) != "\x82" {
+		t.Errorf("got % X, want 82 (PC437 é)", result)
+	}
+	if len(display.downloads) != 0 {
+		t.Errorf("downloads = %v, want none", display.downloads)
+	}
+}
+
+func TestGlyphLRUEviction(t *testing.T) {
+	enc := NewCharsetEncoder()
+	enc.SetGlyphSlots([]byte{0xB0, 0xB1})
+	display := newMockDisplay()
+
+	for _, r := range "✓✗★" {
+		enc.DefineGlyph(r, checkMark)
+	}
+	enc.EncodeTextWithAutoCharsetSwitching("✓", display) // ✓ -> B0
+	enc.EncodeTextWithAutoCharsetSwitching("✗", display) // ✗ -> B1
+	enc.EncodeTextWithAutoCharsetSwitching("✓", display) // ✓ used again, ✗ is now LRU
+
+	result, _ := enc.EncodeTextWithAutoCharsetSwitching("★", display)
+	if result[0] != 0xB1 {
+		t.Errorf("★ placed in %#02x, want 0xB1 (evicting least recently used ✗)", result[0])
+	}
+	slots := enc.GlyphSlots()
+	if slots[0xB0] != '✓' || slots[0xB1] != '★' {
+		t.Errorf("glyph slots = %q, want ✓ in B0 and ★ in B1", slots)
+	}
+
+	// Three distinct glyphs in one text: only two fit
+	result, _ = enc.EncodeTextWithAutoCharsetSwitching("✓✗★", display)
+	if q := countByte(result, '?'); q != 1 {
+		t.Errorf("got % X, want exactly one '?' for the glyph without a slot", result)
+	}
+}
+
+func TestGlyphSlotFreedWhenTextNeedsBuiltinCharacter(t *testing.T) {
+	enc := NewCharsetEncoder()
+	enc.SetGlyphSlots([]byte{'~'})
+	display := newMockDisplay()
+
+	enc.DefineGlyph('✓', checkMark)
+	enc.EncodeTextWithAutoCharsetSwitching("✓", display)
+
+	// A literal '~' would render as ✓ while the slot is occupied
+	result, _ := enc.EncodeTextWithAutoCharsetSwitching("a~b", display)
+	if string(result) != "a~b" {
+		t.Errorf("got %q, want %q", result, "a~b")
+	}
+	if len(display.cancels) != 1 || display.cancels[0] != '~' {
+		t.Errorf("cancels = %v, want ['~']", display.cancels)
+	}
+	if len(enc.GlyphSlots()) != 0 {
+		t.Errorf("glyph slots = %v after cancel, want empty", enc.GlyphSlots())
+	}
+}
+
+func TestGlyphSlotsAvoidKatakana(t *testing.T) {
+	enc := NewCharsetEncoder()
+	if err := enc.SetCodePages(types.CodePageTable{types.CodePageKatakana: 0}); err != nil {
+		t.Fatalf("SetCodePages: %v", err)
+	}
+	display := newMockDisplay()
+	enc.DefineGlyph('✓', checkMark)
+
+	result, _ := enc.EncodeTextWithAutoCharsetSwitching("ｱｲｳ✓", display)
+	if want := []byte{0xB1, 0xB2, 0xB3, katakanaGlyphSlots[0]}; string(result) != string(want) {
+		t.Errorf("got % X, want % X", result, want)
+	}
+	// Katakana text leaves the downloaded glyph alone
+	result, _ = enc.EncodeTextWithAutoCharsetSwitching("ｰｴｹ✓", display)
+	if want := []byte{0xB0, 0xB4, 0xB9, katakanaGlyphSlots[0]}; string(result) != string(want) {
+		t.Errorf("got % X, want % X", result, want)
+	}
+	if len(display.downloads) != 1 || len(display.cancels) != 0 {
+		t.Errorf("downloads = %v, cancels = %v; want one download and no cancels", display.downloads, display.cancels)
+	}
+}
+
+func TestDefineGlyphRejectsWideRows(t *testing.T) {
+	enc := NewCharsetEncoder()
+	if _, _, err := enc.DefineGlyph('✓', types.Glyph{0b100000}); err == nil {
+		t.Fatal("expected error for 6-dot row, got nil")
+	}
+}
+
+func countByte(data []byte, b byte) int {
+	n := 0
+	for _, c := range data {
+		if c == b {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,35 @@
+package escpos
+
+import "unicode/utf8"
+
+// The HD44780 character ROM (A00), built into Matrix Orbital modules and
+// their clones, holds ASCII except for the codes in hd44780ASCII, half-width
+// Katakana at 0xA1-0xDF as in JIS X 0201, and symbols at 0xE0-0xFF.
+
+// hd44780ASCII maps the ASCII codes the ROM shows as other characters to
+// those characters. '\' and '~' are not in the ROM at all.
+var hd44780ASCII = map[byte]rune{0x5C: '¥', 0x7E: '→', 0x7F: '←'}
+
+// hd44780Symbols lists the characters at 0xE0-0xFF. Zero marks glyphs with
+// no Unicode equivalent, such as letters with descenders, which the encoder
+// never produces.
+var hd44780Symbols = [32]rune{
+	'α', 'ä', 'β', 'ε', 'μ', 'σ', 'ρ', 0, '√', 0, 0, 0, '¢', 0, 'ñ', 'ö',
+	0, 0, 'θ', '∞', 'Ω', 'ü', 'Σ', 'π', 0, 0, '千', '万', '円', '÷', 0, '█',
+}
+
+// hd44780Rune returns the character the ROM shows at b, or U+FFFD if it has
+// none.
+func hd44780Rune(b byte) rune {
+	switch r, replaced := hd44780ASCII[b]; {
+	case replaced:
+		return r
+	case b < 0x80:
+		return rune(b)
+	case b >= katakanaByteOffset && b <= katakanaByteOffset+(halfWidthKatakanaLast-halfWidthKatakanaFirst):
+		return halfWidthKatakanaFirst + rune(b-katakanaByteOffset)
+	case b >= 0xE0 && hd44780Symbols[b-0xE0] != 0:
+		return hd44780Symbols[b-0xE0]
+	}
+	return utf8.RuneError
+}
@@ -0,0 +1,105 @@
+package escpos
+
+import "github.com/corrreia/govfd/types"
+
+// internationalPositions are the ASCII codes an international character set
+// (ESC R) may replace.
+var internationalPositions = [12]byte{0x23, 0x24, 0x40, 0x5B, 0x5C, 0x5D, 0x5E, 0x60, 0x7B, 0x7C, 0x7D, 0x7E}
+
+// internationalCharset describes one ESC R character set.
+type internationalCharset struct {
+	id    types.InternationalCharset
+	n     byte     // ESC R parameter
+	chars [12]rune // characters shown at internationalPositions
+}
+
+// internationalCharsets lists the ESC R character sets in parameter order.
+// USA comes first, so searches prefer plain ASCII.
+var internationalCharsets = []internationalCharset{
+	{types.InternationalUSA, 0, [12]rune{'#', '$', '@', '[', '\\', ']', '^', '`', '{', '|', '}', '~'}},
+	{types.InternationalFrance, 1, [12]rune{'#', '$', 'à', '°', 'ç', '§', '^', '`', 'é', 'ù', 'è', '¨'}},
+	{types.InternationalGermany, 2, [12]rune{'#', '$', '§', 'Ä', 'Ö', 'Ü', '^', '`', 'ä', 'ö', 'ü', 'ß'}},
+	{types.InternationalUK, 3, [12]rune{'£', '$', '@', '[', '\\', ']', '^', '`', '{', '|', '}', '~'}},
+	{types.InternationalDenmarkI, 4, [12]rune{'#', '$', '@', 'Æ', 'Ø', 'Å', '^', '`', 'æ', 'ø', 'å', '~'}},
+	{types.InternationalSweden, 5, [12]rune{'#', '¤', 'É', 'Ä', 'Ö', 'Å', 'Ü', 'é', 'ä', 'ö', 'å', 'ü'}},
+	{types.InternationalItaly, 6, [12]rune{'#', '$', '@', '°', '\\', 'é', '^', 'ù', 'à', 'ò', 'è', 'ì'}},
+	{types.InternationalSpainI, 7, [12]rune{'₧', '$', '@', '¡', 'Ñ', '¿', '^', '`', '¨', 'ñ', '}', '~'}},
+	{types.InternationalJapan, 8, [12]rune{'#', '$', '@', '[', '¥', ']', '^', '`', '{', '|', '}', '~'}},
+	{types.InternationalNorway, 9, [12]rune{'#', '¤', 'É', 'Æ', 'Ø', 'Å', 'Ü', 'é', 'æ', 'ø', 'å', 'ü'}},
+	{types.InternationalDenmarkII, 10, [12]rune{'#', '$', 'É', 'Æ', 'Ø', 'Å', 'Ü', 'é', 'æ', 'ø', 'å', 'ü'}},
+	{types.InternationalSpainII, 11, [12]rune{'#', '$', 'á', '¡', 'Ñ', '¿', 'é', '`', 'í', 'ñ', 'ó', 'ú'}},
+	{types.InternationalLatinAmerica, 12, [12]rune{'#', '$', 'á', '¡', 'Ñ', '¿', 'é', 'ü', 'í', 'ñ', 'ó', 'ú'}},
+	{types.InternationalKorea, 13, [12]rune{'#', '$', '@', '[', '₩', ']', '^', '`', '{', '|', '}', '~'}},
+}
+
+// lookupInternationalCharset returns the description of an ESC R character set.
+func lookupInternationalCharset(id types.InternationalCharset) (*internationalCharset, bool) {
+	for i := range internationalCharsets {
+		if internationalCharsets[i].id == id {
+			return &internationalCharsets[i], true
+		}
+	}
+	return nil, false
+}
+
+// replaces reports whether the set shows something other than ASCII at code.
+func (c *internationalCharset) replaces(code byte) bool {
+	for i, pos := range internationalPositions {
+		if pos == code {
+			return c.chars[i] != rune(code)
+		}
+	}
+	return false
+}
+
+// replacesAny reports whether any byte of data would show a non-ASCII
+// character under this set.
+func (c *internationalCharset) replacesAny(data []byte) bool {
+	if c.id == types.InternationalUSA {
+		return false
+	}
+	for _, b := range data {
+		if c.replaces(b) {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeRune returns the replaced position showing r, if the set has one.
+func (c *internationalCharset) encodeRune(r rune) (byte, bool) {
+	for i, ch := range c.chars {
+		if ch == r && rune(internationalPositions[i]) != r {
+			return internationalPositions[i], true
+		}
+	}
+	return 0, false
+}
+
+// encodeRuneWithInternational returns the byte for r in table t while the
+// international set intl is active: replaced positions show intl's characters
+// and can no longer show their ASCII ones.
+func encodeRuneWithInternational(t *runeTable, intl *internationalCharset, r rune) (byte, bool) {
+	if b, ok := intl.encodeRune(r); ok {
+		return b, true
+	}
+	b, ok := t.encodeRune(r)
+	if ok && intl.replaces(b) {
+		return 0, false
+	}
+	return b, ok
+}
+
+// anyInternationalHas reports whether an international set the encoder may
+// select shows r.
+func (e *CharsetEncoder) anyInternationalHas(r rune) bool {
+	if e.fixedIntl {
+		return false
+	}
+	for i := range internationalCharsets {
+		if _, ok := internationalCharsets[i].encodeRune(r); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+package escpos
+
+import (
+	"testing"
+
+	"github.com/corrreia/govfd/types"
+)
+
+func TestInternationalSetAvoidsCodeTableSwitch(t *testing.T) {
+	enc := NewCharsetEncoder()
+	display := newMockDisplay()
+
+	// Switch to PC860 for Portuguese; PC860 has no ¥
+	enc.EncodeTextWithAutoCharsetSwitching("ação", display)
+	switches := display.switchCount
+
+	result, err := enc.EncodeTextWithAutoCharsetSwitching("¥500", display)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(result) != "\\500" {
+		t.Errorf("got %q, want %q (¥ at 0x5C in the Japan set)", result, "\\500")
+	}
+	if display.switchCount != switches {
+		t.Errorf("code table switched %d times, want 0", display.switchCount-switches)
+	}
+	if len(display.intl) != 1 || display.intl[0] != types.InternationalJapan {
+		t.Errorf("international sets selected = %v, want [JAPAN]", display.intl)
+	}
+}
+
+func TestInternationalSetRestoredForReplacedASCII(t *testing.T) {
+	enc := NewCharsetEncoder()
+	display := newMockDisplay()
+	enc.SetInternationalCharset(types.InternationalUK)
+
+	// '#' is £ in the UK set — must go back to USA
+	result, err := enc.EncodeTextWithAutoCharsetSwitching("Item #3", display)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(result) != "Item #3" {
+		t.Errorf("got %q, want %q", result, "Item #3")
+	}
+	if len(display.intl) != 1 || display.intl[0] != types.InternationalUSA {
+		t.Errorf("international sets selected = %v, want [USA]", display.intl)
+	}
+}
+
+func TestInternationalSetKeptWhenTextAvoidsReplacedPositions(t *testing.T) {
+	enc := NewCharsetEncoder()
+	display := newMockDisplay()
+	enc.SetInternationalCharset(types.InternationalUK)
+
+	enc.EncodeTextWithAutoCharsetSwitching("Hello", display)
+	if len(display.intl) != 0 {
+		t.Errorf("international sets selected = %v, want none", display.intl)
+	}
+}
+
+func TestInternationalSetRestoredForSanitizedText(t *testing.T) {
+	enc := NewCharsetEncoder()
+	display := newMockDisplay()
+	enc.SetInternationalCharset(types.InternationalGermany)
+
+	// Unrepresentable CJK sanitizes to '?', but '[' would read as Ä
+	result, _ := enc.EncodeTextWithAutoCharsetSwitching("[日本]", display)
+	if string(result) != "[??]" {
+		t.Errorf("got %q, want %q", result, "[??]")
+	}
+	if len(display.intl) != 1 || display.intl[0] != types.InternationalUSA {
+		t.Errorf("international sets selected = %v, want [USA]", display.intl)
+	}
+}
+
+func TestSetInternationalCharsetCommand(t *testing.T) {
+	p := &ESCPOSProtocol{}
+	if got, err := p.SetInternationalCharset(types.InternationalUK); err != nil || string(got) != "\x1bR\x03" {
+		t.Errorf("SetInternationalCharset(UK) = % X, %v; want 1B 52 03", got, err)
+	}
+	if got, err := p.SetInternationalCharset("ATLANTIS"); err == nil {
+		t.Errorf("SetInternationalCharset(unknown) = % X, want an error", got)
+	}
+}
+
+func TestInternationalSwitchingDisabled(t *testing.T) {
+	enc := NewCharsetEncoder()
+	enc.SetInternationalSwitching(false)
+	display := newMockDisplay()
+
+	enc.EncodeTextWithAutoCharsetSwitching("ação", display)
+	result, err := enc.EncodeTextWithAutoCharsetSwitching("¥500", display)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(result) != "\x9D500" {
+		t.Errorf("got % X, want 9D 35 30 30 (¥ in PC437)", result)
+	}
+	if len(display.intl) != 0 {
+		t.Errorf("international sets selected = %v, want none", display.intl)
+	}
+}
+
+func TestInternationalSetPreferredOverApproximation(t *testing.T) {
+	tests := []struct {
+		name  string
+		pages types.CodePageTable
+		input string
+		want  string
+		intl  types.InternationalCharset
+	}{
+		{"Korea", nil, "₩100", "\\100", types.InternationalKorea},
+		{"Japan", types.CodePageTable{types.CodePagePC860: 3}, "¥500", "\\500", types.InternationalJapan},
+	}
+	for _, tt := range tests {
+		enc := NewCharsetEncoder()
+		if tt.pages != nil {
+			if err := enc.SetCodePages(tt.pages); err != nil {
+				t.Fatalf("%s: SetCodePages: %v", tt.name, err)
+			}
+		}
+		enc.SetInternationalSwitching(true)
+		display := newMockDisplay()
+
+		result, err := enc.EncodeTextWithAutoCharsetSwitching(tt.input, display)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if string(result) != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, result, tt.want)
+		}
+		if len(display.intl) != 1 || display.intl[0] != tt.intl {
+			t.Errorf("%s: international sets selected = %v, want [%s]", tt.name, display.intl, tt.intl)
+		}
+	}
+
+	// Without international sets the approximation is still used
+	enc := NewCharsetEncoder()
+	enc.SetInternationalSwitching(false)
+	if result, _ := enc.EncodeTextWithAutoCharsetSwitching("₩100", newMockDisplay()); string(result) != "KRW100" {
+		t.Errorf("fixed international set: got %q, want %q", result, "KRW100")
+	}
+}
@@ -0,0 +1,64 @@
+package escpos
+
+import (
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+	"golang.org/x/text/width"
+)
+
+// Half-width Katakana block (U+FF61..U+FF9F) maps one-to-one onto bytes
+// 0xA1..0xDF of the ESC/POS Katakana page, as in JIS X 0201.
+const (
+	halfWidthKatakanaFirst = '｡'
+	halfWidthKatakanaLast  = 'ﾟ'
+	katakanaByteOffset     = 0xA1
+)
+
+// isHalfWidthKatakana reports whether r is in the half-width Katakana block,
+// which also holds the half-width Japanese punctuation.
+func isHalfWidthKatakana(r rune) bool {
+	return r >= halfWidthKatakanaFirst && r <= halfWidthKatakanaLast
+}
+
+// isFullWidthKatakana reports whether r is a full-width Katakana letter or
+// one of the Japanese punctuation marks that have a half-width form.
+func isFullWidthKatakana(r rune) bool {
+	switch r {
+	case '、', '。', '「', '」', '・', 'ー':
+		return true
+	}
+	return r >= 'ァ' && r <= 'ヺ'
+}
+
+// foldKatakana rewrites full-width Katakana as half-width Katakana so it can
+// be shown on the Katakana page. Voiced letters are split into the base
+// letter plus a separate (han)dakuten mark, e.g. "ガ" becomes "ｶﾞ".
+// Letters without a half-width form and all other runes are left unchanged.
+func foldKatakana(text string) string {
+	if !strings.ContainsFunc(text, isFullWidthKatakana) {
+		return text
+	}
+	var b strings.Builder
+	b.Grow(len(text))
+	for _, r := range text {
+		if folded, ok := foldKatakanaRune(r); ok {
+			b.WriteString(folded)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// foldKatakanaRune returns the half-width form of a full-width Katakana rune.
+func foldKatakanaRune(r rune) (string, bool) {
+	if !isFullWidthKatakana(r) {
+		return "", false
+	}
+	folded := width.Narrow.String(norm.NFD.String(string(r)))
+	if strings.ContainsFunc(folded, func(f rune) bool { return !isHalfWidthKatakana(f) }) {
+		return "", false
+	}
+	return folded, true
+}
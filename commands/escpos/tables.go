@@ -0,0 +1,178 @@
+package escpos
+
+import (
+	"unicode/utf8"
+
+	"github.com/corrreia/govfd/types"
+)
+
+// pageSet is a set of code tables, one bit per entry of codePageOrder.
+type pageSet uint8
+
+// codePageOrder lists the code tables the encoder knows. A table's index is
+// its bit in a pageSet.
+var codePageOrder = [...]types.CodePage{
+	types.CodePagePC437,
+	types.CodePageKatakana,
+	types.CodePagePC850,
+	types.CodePagePC860,
+	types.CodePagePC858,
+	types.CodePageHD44780,
+}
+
+// runeTable is the precomputed rune to byte mapping of one code table.
+// ASCII maps to itself wherever the table has it and is not stored. Other
+// runes are looked up in blocks of 256 (all tables are within U+0000..U+FFFF).
+type runeTable struct {
+	codePage types.CodePage
+	bit      pageSet
+	blocks   [256]*[256]byte
+}
+
+// encodeRune returns the byte for r in the table.
+func (t *runeTable) encodeRune(r rune) (byte, bool) {
+	if pagesWith(r)&t.bit == 0 {
+		return 0, false
+	}
+	if r < utf8.RuneSelf {
+		return byte(r), true
+	}
+	return t.blocks[r>>8][r&0xFF], true
+}
+
+// firstMissing returns the first rune of text the table lacks, if any.
+func (t *runeTable) firstMissing(text string) (rune, bool) {
+	for _, r := range text {
+		if pagesWith(r)&t.bit == 0 {
+			return r, true
+		}
+	}
+	return 0, false
+}
+
+var (
+	// runeTables holds the table of each entry of codePageOrder.
+	runeTables [len(codePageOrder)]*runeTable
+
+	// asciiCoverage holds, for every ASCII character, the set of tables
+	// that show it as itself.
+	asciiCoverage [utf8.RuneSelf]pageSet
+
+	// coverage holds, for every non-ASCII rune, the set of tables that have
+	// it, in blocks of 256 runes. Blocks no table touches are nil.
+	coverage [256]*[256]pageSet
+)
+
+func init() {
+	for i, codePage := range codePageOrder {
+		t := &runeTable{codePage: codePage, bit: 1 << i}
+		set := func(r rune, b byte) {
+			hi, lo := r>>8, r&0xFF
+			if t.blocks[hi] == nil {
+				t.blocks[hi] = new([256]byte)
+			}
+			if coverage[hi] == nil {
+				coverage[hi] = new([256]pageSet)
+			}
+			t.blocks[hi][lo] = b
+			coverage[hi][lo] |= t.bit
+		}
+		switch codePage {
+		case types.CodePageKatakana:
+			for r := rune(halfWidthKatakanaFirst); r <= halfWidthKatakanaLast; r++ {
+				set(r, byte(r-halfWidthKatakanaFirst)+katakanaByteOffset)
+			}
+		case types.CodePageHD44780:
+			for b := 0x80; b <= 0xFF; b++ {
+				if r := hd44780Rune(byte(b)); r != utf8.RuneError {
+					set(r, byte(b))
+				}
+			}
+			for b, r := range hd44780ASCII {
+				set(r, b)
+			}
+		default:
+			cm := latinCodePages[codePage]
+			for b := 0x80; b <= 0xFF; b++ {
+				if r := cm.DecodeByte(byte(b)); r != utf8.RuneError {
+					set(r, byte(b))
+				}
+			}
+		}
+		for b := range asciiCoverage {
+			if _, replaced := hd44780ASCII[byte(b)]; !replaced || codePage != types.CodePageHD44780 {
+				asciiCoverage[b] |= t.bit
+			}
+		}
+		runeTables[i] = t
+	}
+}
+
+// tableFor returns the table of codePage, or nil if the encoder does not
+// know it.
+func tableFor(codePage types.CodePage) *runeTable {
+	for i, known := range codePageOrder {
+		if known == codePage {
+			return runeTables[i]
+		}
+	}
+	return nil
+}
+
+// pagesOf returns the set of the given code tables. Unknown tables are ignored.
+func pagesOf(codePages types.CodePageTable) pageSet {
+	var set pageSet
+	for codePage := range codePages {
+		if t := tableFor(codePage); t != nil {
+			set |= t.bit
+		}
+	}
+	return set
+}
+
+// pagesWith returns the code tables that have r.
+func pagesWith(r rune) pageSet {
+	if r < utf8.RuneSelf {
+		return asciiCoverage[r]
+	}
+	if r > 0xFFFF || coverage[r>>8] == nil {
+		return 0
+	}
+	return coverage[r>>8][r&0xFF]
+}
+
+// pagesCovering returns the code tables that have every rune of text.
+func pagesCovering(text string) pageSet {
+	set := ^pageSet(0)
+	for _, r := range text {
+		if set &= pagesWith(r); set == 0 {
+			break
+		}
+	}
+	return set
+}
+
+// pageList is an ordered list of distinct code tables. It has room for every
+// known table, so building one does not allocate.
+type pageList struct {
+	n     int
+	pages [len(codePageOrder)]types.CodePage
+}
+
+// add appends codePage unless it is already listed.
+func (l *pageList) add(codePage types.CodePage) {
+	for _, listed := range l.pages[:l.n] {
+		if listed == codePage {
+			return
+		}
+	}
+	if l.n < len(l.pages) {
+		l.pages[l.n] = codePage
+		l.n++
+	}
+}
+
+// slice returns the listed code tables.
+func (l *pageList) slice() []types.CodePage {
+	return l.pages[:l.n]
+}
@@ -0,0 +1,119 @@
+package escpos
+
+import (
+	"testing"
+	"unicode/utf8"
+
+	"github.com/corrreia/govfd/types"
+)
+
+func TestRuneTablesMatchCharmaps(t *testing.T) {
+	for codePage, cm := range latinCodePages {
+		for r := rune(0); r <= 0xFFFF; r++ {
+			want, wantOK := cm.EncodeRune(r)
+			got, ok := encodeRuneInCodePage(codePage, r)
+			if ok != wantOK || (ok && got != want) {
+				t.Fatalf("%s: rune %U = %#02x, %v; charmap says %#02x, %v", codePage, r, got, ok, want, wantOK)
+			}
+			if ok != (pagesWith(r)&tableFor(codePage).bit != 0) {
+				t.Fatalf("%s: coverage of %U disagrees with table", codePage, r)
+			}
+		}
+	}
+	for r := rune(0); r <= 0xFFFF; r++ {
+		_, ok := encodeRuneInCodePage(types.CodePageKatakana, r)
+		if want := r < utf8.RuneSelf || isHalfWidthKatakana(r); ok != want {
+			t.Fatalf("Katakana: rune %U representable = %v, want %v", r, ok, want)
+		}
+	}
+}
+
+func TestHD44780Table(t *testing.T) {
+	for r := rune(0); r <= 0xFFFF; r++ {
+		b, ok := encodeRuneInCodePage(types.CodePageHD44780, r)
+		if ok != (pagesWith(r)&tableFor(types.CodePageHD44780).bit != 0) {
+			t.Fatalf("HD44780: coverage of %U disagrees with table", r)
+		}
+		if ok && hd44780Rune(b) != r {
+			t.Fatalf("HD44780: rune %U = %#02x, which shows %U", r, b, hd44780Rune(b))
+		}
+	}
+	for _, r := range `\~` {
+		if _, ok := encodeRuneInCodePage(types.CodePageHD44780, r); ok {
+			t.Errorf("HD44780 has %q, which the ROM lacks", r)
+		}
+	}
+	for r, want := range map[rune]byte{'A': 'A', '¥': 0x5C, '→': 0x7E, '←': 0x7F, 'ｱ': 0xB1, 'π': 0xF7} {
+		if b, ok := encodeRuneInCodePage(types.CodePageHD44780, r); !ok || b != want {
+			t.Errorf("HD44780: rune %q = %#02x, %v; want %#02x", r, b, ok, want)
+		}
+	}
+}
+
+func TestEncodeDoesNotAllocateBeyondResult(t *testing.T) {
+	tests := []struct {
+		name  string
+		texts []string
+	}{
+		{"current page", []string{"Café crème"}},
+		{"page switch", []string{"Ação", "€19.99"}},
+		{"best effort", []string{"Ação 日本"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enc := NewCharsetEncoder()
+			display := newMockDisplay()
+			i := 0
+			allocs := testing.AllocsPerRun(100, func() {
+				text := tt.texts[i%len(tt.texts)]
+				i++
+				if _, err := enc.EncodeTextWithAutoCharsetSwitching(text, display); err != nil {
+					t.Fatal(err)
+				}
+			})
+			if allocs > 1 {
+				t.Errorf("%v allocations per encode, want 1 (the result)", allocs)
+			}
+		})
+	}
+}
+
+// benchmarkText is typical of a marquee or clock redrawn many times a second.
+const benchmarkText = "Promoção: café 2,50€"
+
+func BenchmarkEncodeCurrentPage(b *testing.B) {
+	enc := NewCharsetEncoder()
+	display := newMockDisplay()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		enc.EncodeTextWithAutoCharsetSwitching(benchmarkText, display)
+	}
+}
+
+func BenchmarkEncodeWithSwitch(b *testing.B) {
+	enc := NewCharsetEncoder()
+	display := newMockDisplay()
+	texts := []string{"Ação", "€19.99"}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		enc.EncodeTextWithAutoCharsetSwitching(texts[i%2], display)
+	}
+}
+
+func BenchmarkEncodeBestEffort(b *testing.B) {
+	enc := NewCharsetEncoder()
+	display := newMockDisplay()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		enc.EncodeTextWithAutoCharsetSwitching("Ação 日本", display)
+	}
+}
+
+// BenchmarkXTextEncoder is the former path: a fresh x/text encoder per
+// detection, run through the generic transformer.
+func BenchmarkXTextEncoder(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		latinCodePages[types.CodePagePC858].NewEncoder().String(benchmarkText)
+	}
+}
@@ -0,0 +1,69 @@
+package escpos
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/corrreia/govfd/types"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// ErrInvalidUTF8 is returned for text that is not valid UTF-8 when the
+// encoder's policy is types.InvalidUTF8Reject.
+var ErrInvalidUTF8 = errors.New("text is not valid UTF-8")
+
+// SetInvalidUTF8Policy sets how text that is not valid UTF-8 is handled.
+func (e *CharsetEncoder) SetInvalidUTF8Policy(policy types.InvalidUTF8Policy) error {
+	if policy < types.InvalidUTF8Replace || policy > types.InvalidUTF8CP1252 {
+		return fmt.Errorf("unknown invalid UTF-8 policy %d", int(policy))
+	}
+	e.invalidUTF8 = policy
+	return nil
+}
+
+// makeValidUTF8 applies the invalid UTF-8 policy to text, which must not be
+// valid UTF-8. Valid sequences are kept; each run of invalid bytes becomes
+// one '?', as with strings.ToValidUTF8, or each invalid byte the character it
+// stands for in Latin-1 or Windows-1252. It also returns the offsets of the
+// invalid runs replaced with '?' and, if track is set, the offset in text of
+// each byte of the result.
+func (e *CharsetEncoder) makeValidUTF8(text string, track bool) (string, []int, []int, error) {
+	var b strings.Builder
+	b.Grow(len(text) + 8)
+	var replaced, origins []int
+	invalid := false // the previous byte was replaced with '?'
+	for offset := 0; offset < len(text); {
+		r, size := utf8.DecodeRuneInString(text[offset:])
+		if r == utf8.RuneError && size == 1 {
+			switch e.invalidUTF8 {
+			case types.InvalidUTF8Reject:
+				return "", nil, nil, fmt.Errorf("%w: invalid byte %#02x at offset %d", ErrInvalidUTF8, text[offset], offset)
+			case types.InvalidUTF8Latin1:
+				r = rune(text[offset])
+			case types.InvalidUTF8CP1252:
+				r = charmap.Windows1252.DecodeByte(text[offset])
+			default:
+				if invalid {
+					offset++
+					continue
+				}
+				r = '?'
+				replaced = append(replaced, offset)
+				invalid = true
+			}
+		} else {
+			invalid = false
+		}
+		n, _ := b.WriteRune(r)
+		if track {
+			for i := 0; i < n; i++ {
+				origins = append(origins, offset)
+			}
+		}
+		offset += size
+	}
+	return b.String(), replaced, origins, nil
+}
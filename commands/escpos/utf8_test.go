@@ -0,0 +1,76 @@
+package escpos
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/corrreia/govfd/types"
+)
+
+func TestInvalidUTF8Policies(t *testing.T) {
+	tests := []struct {
+		policy types.InvalidUTF8Policy
+		text   string
+		want   []byte
+		page   int
+	}{
+		{types.InvalidUTF8Replace, "Caf\xe9", []byte("Caf?"), chartablePC437},
+		{types.InvalidUTF8Replace, "a\xc3(b", []byte("a?(b"), chartablePC437},      // truncated sequence
+		{types.InvalidUTF8Replace, "a\xe2\x82b", []byte("a?b"), chartablePC437},    // truncated 3-byte sequence
+		{types.InvalidUTF8Replace, "\xff\xfe!\xff", []byte("?!?"), chartablePC437}, // one '?' per invalid run
+		{types.InvalidUTF8Latin1, "Caf\xe9", []byte{'C', 'a', 'f', 0x82}, chartablePC437},
+		{types.InvalidUTF8Latin1, "a\xe7\xe3o", []byte{'a', 0x87, 0x84, 'o'}, chartablePC860},
+		{types.InvalidUTF8CP1252, "\x8019", []byte{0xD5, '1', '9'}, chartablePC858}, // 0x80 is € in CP1252
+		{types.InvalidUTF8Latin1, "ação \xe9", []byte{'a', 0x87, 0x84, 'o', ' ', 0x82}, chartablePC860},
+	}
+
+	for _, tt := range tests {
+		enc := NewCharsetEncoder()
+		enc.SetInvalidUTF8Policy(tt.policy)
+		display := newMockDisplay()
+
+		result, err := enc.EncodeTextWithAutoCharsetSwitching(tt.text, display)
+		if err != nil {
+			t.Fatalf("policy %d, EncodeText(%q): unexpected error: %v", tt.policy, tt.text, err)
+		}
+		if string(result) != string(tt.want) {
+			t.Errorf("policy %d, EncodeText(%q) = % X, want % X", tt.policy, tt.text, result, tt.want)
+		}
+		if display.currentPage != tt.page {
+			t.Errorf("policy %d, EncodeText(%q): hardware page = %d, want %d", tt.policy, tt.text, display.currentPage, tt.page)
+		}
+	}
+}
+
+func TestInvalidUTF8Reject(t *testing.T) {
+	enc := NewCharsetEncoder()
+	enc.SetInvalidUTF8Policy(types.InvalidUTF8Reject)
+
+	_, err := enc.EncodeTextWithAutoCharsetSwitching("ok\xff", newMockDisplay())
+	if !errors.Is(err, ErrInvalidUTF8) {
+		t.Fatalf("error = %v, want ErrInvalidUTF8", err)
+	}
+}
+
+func TestInvalidUTF8ReportOffsets(t *testing.T) {
+	enc := NewCharsetEncoder()
+
+	// ガ (3 bytes) is folded, the stray byte after it is replaced
+	_, report, err := enc.EncodeTextWithReport("ガ\xffム", newMockDisplay())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []types.Substitution{
+		{Offset: 0, Rune: 'ガ', Replacement: "ｶﾞ"},
+		{Offset: 3, Rune: utf8.RuneError, Replacement: "?", Replaced: true},
+		{Offset: 4, Rune: 'ム', Replacement: "ﾑ"},
+	}
+	if !reflect.DeepEqual(report.Substitutions, want) {
+		t.Errorf("substitutions = %+v, want %+v", report.Substitutions, want)
+	}
+	if report.Cells != 4 {
+		t.Errorf("cells = %d, want 4", report.Cells)
+	}
+}
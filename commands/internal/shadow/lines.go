@@ -0,0 +1,128 @@
+package shadow
+
+import (
+	"fmt"
+
+	"github.com/corrreia/govfd/types"
+)
+
+// Lines builds the command showing text on a 1-based row of a line
+// protocol.
+type Lines interface {
+	Line(row int, text []byte) []byte
+}
+
+// LineProtocol implements the commands shared by protocols that only replace
+// whole lines: it keeps a shadow copy of the screen, so writing text or
+// moving the cursor resends the affected lines built by L. Protocols embed it
+// and add their name, description, Clear and FormFeed.
+//
+// A command changes the shadow screen only once Commit confirms it was
+// written to the display. The zero value, as registered, keeps no state:
+// each command starts from a blank 20x2 screen. Displays get their own
+// screen from Sized.
+type LineProtocol[L Lines] struct {
+	lines   L
+	screen  *Screen // what the display shows, nil in the zero value
+	pending *Screen // screen after the last command built, until Commit
+}
+
+// Sized returns a line protocol with its own shadow screen of the model's
+// size, for the embedding protocol's ForModel.
+func (p *LineProtocol[L]) Sized(profile *types.ModelProfile) (LineProtocol[L], error) {
+	if profile.Columns < 1 || profile.Rows < 1 || profile.Rows > 2 {
+		return LineProtocol[L]{}, fmt.Errorf("%dx%d screen: line commands address one or two lines", profile.Columns, profile.Rows)
+	}
+	return LineProtocol[L]{lines: p.lines, screen: New(profile.Columns, profile.Rows)}, nil
+}
+
+// edit returns the screen the next command changes: a copy of the shadow
+// screen, applied by Commit, or a blank screen in the zero value.
+func (p *LineProtocol[L]) edit() *Screen {
+	if p.screen == nil {
+		return New(20, 2)
+	}
+	p.pending = p.screen.Clone()
+	return p.pending
+}
+
+// Commit applies the last command built to the shadow screen, once the
+// command was written to the display.
+func (p *LineProtocol[L]) Commit() {
+	if p.pending != nil {
+		p.screen, p.pending = p.pending, nil
+	}
+}
+
+// Cursor returns the 1-based position of the emulated cursor, or (0, 0) in
+// the zero value.
+func (p *LineProtocol[L]) Cursor() (column, row int) {
+	if p.screen == nil {
+		return 0, 0
+	}
+	return p.screen.Cursor()
+}
+
+// ResetScreen blanks the shadow screen, for Clear and FormFeed, and returns
+// its rows.
+func (p *LineProtocol[L]) ResetScreen() [][]byte {
+	screen := p.edit()
+	screen.Reset()
+	return screen.cells
+}
+
+// MoveCursor moves the emulated cursor (1-based) and resends its line from
+// the shadow screen.
+func (p *LineProtocol[L]) MoveCursor(column, row int) ([]byte, error) {
+	screen := p.edit()
+	if err := screen.MoveTo(column, row); err != nil {
+		return nil, err
+	}
+	return p.lines.Line(row, screen.Line(row)), nil
+}
+
+// WriteText puts encoded text at the emulated cursor and returns the
+// commands resending every line it changed.
+func (p *LineProtocol[L]) WriteText(text []byte) ([]byte, error) {
+	if err := CheckText(text); err != nil {
+		return nil, err
+	}
+	screen := p.edit()
+	return screen.Render(screen.Write(text), p.lines.Line), nil
+}
+
+// UploadLine returns the command replacing a whole row with encoded text.
+func (p *LineProtocol[L]) UploadLine(row int, text []byte) ([]byte, error) {
+	if err := CheckText(text); err != nil {
+		return nil, err
+	}
+	screen := p.edit()
+	if err := screen.SetLine(row, text); err != nil {
+		_, rows := screen.Size()
+		if rows == 1 {
+			return nil, fmt.Errorf("line upload row must be 1, got %d", row)
+		}
+		return nil, fmt.Errorf("line upload row must be between 1 and %d, got %d", rows, row)
+	}
+	return p.lines.Line(row, screen.Line(row)), nil
+}
+
+// SetBrightness reports that line protocols have no brightness command.
+func (p *LineProtocol[L]) SetBrightness(level int) ([]byte, error) {
+	return nil, fmt.Errorf("brightness: %w", types.ErrUnsupported)
+}
+
+// SetBlink reports that line protocols have no cursor.
+func (p *LineProtocol[L]) SetBlink(intervalMs int) ([]byte, error) {
+	return nil, fmt.Errorf("cursor blink: %w", types.ErrUnsupported)
+}
+
+// SetCharset reports that the character set is fixed.
+func (p *LineProtocol[L]) SetCharset(page int) ([]byte, error) {
+	return nil, fmt.Errorf("code table selection: %w", types.ErrUnsupported)
+}
+
+// SelfTest reports that there is no self-test command.
+func (p *LineProtocol[L]) SelfTest() ([]byte, error) {
+	return nil, fmt.Errorf("self-test: %w", types.ErrUnsupported)
+}
@@ -0,0 +1,123 @@
+// Package shadow keeps a copy of what a display shows, for protocols that
+// can only send whole lines and must emulate cursor addressing.
+package shadow
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// Screen is a shadow copy of a display's character cells and cursor. Writes
+// wrap at the end of a row and from the last row back to the first, like
+// the cursor tracking of govfd.Display.
+type Screen struct {
+	columns, rows int
+	cells         [][]byte
+	column, row   int // 0-based cursor
+}
+
+// New returns a blank screen of the given size with the cursor home.
+func New(columns, rows int) *Screen {
+	s := &Screen{columns: columns, rows: rows, cells: make([][]byte, rows)}
+	for i := range s.cells {
+		s.cells[i] = make([]byte, columns)
+	}
+	s.Reset()
+	return s
+}
+
+// Clone returns an independent copy of the screen.
+func (s *Screen) Clone() *Screen {
+	c := *s
+	c.cells = make([][]byte, len(s.cells))
+	for i, line := range s.cells {
+		c.cells[i] = bytes.Clone(line)
+	}
+	return &c
+}
+
+// Size returns the screen geometry.
+func (s *Screen) Size() (columns, rows int) {
+	return s.columns, s.rows
+}
+
+// Reset blanks the screen and homes the cursor.
+func (s *Screen) Reset() {
+	for _, line := range s.cells {
+		for i := range line {
+			line[i] = ' '
+		}
+	}
+	s.column, s.row = 0, 0
+}
+
+// MoveTo moves the cursor to a 1-based position.
+func (s *Screen) MoveTo(column, row int) error {
+	if column < 1 || column > s.columns || row < 1 || row > s.rows {
+		return errors.New("position outside the screen")
+	}
+	s.column, s.row = column-1, row-1
+	return nil
+}
+
+// Cursor returns the 1-based cursor position.
+func (s *Screen) Cursor() (column, row int) {
+	return s.column + 1, s.row + 1
+}
+
+// Write puts text at the cursor, advancing it, and returns the 1-based rows
+// it changed in the order they were written.
+func (s *Screen) Write(text []byte) []int {
+	var changed []int
+	for _, b := range text {
+		if len(changed) == 0 || changed[len(changed)-1] != s.row+1 {
+			changed = append(changed, s.row+1)
+		}
+		s.cells[s.row][s.column] = b
+		if s.column++; s.column == s.columns {
+			s.column = 0
+			s.row = (s.row + 1) % s.rows
+		}
+	}
+	return changed
+}
+
+// SetLine replaces a 1-based row with text, padded with spaces or cut to
+// the screen width. The cursor does not move.
+func (s *Screen) SetLine(row int, text []byte) error {
+	if row < 1 || row > s.rows {
+		return errors.New("row outside the screen")
+	}
+	line := s.cells[row-1]
+	n := copy(line, text)
+	for i := n; i < len(line); i++ {
+		line[i] = ' '
+	}
+	return nil
+}
+
+// Line returns the contents of a 1-based row.
+func (s *Screen) Line(row int) []byte {
+	return s.cells[row-1]
+}
+
+// Render concatenates the commands that resend the given 1-based rows,
+// built by line from each row's contents.
+func (s *Screen) Render(rows []int, line func(row int, text []byte) []byte) []byte {
+	var seq []byte
+	for _, row := range rows {
+		seq = append(seq, line(row, s.Line(row))...)
+	}
+	return seq
+}
+
+// CheckText rejects control codes, which would end a line command early.
+func CheckText(text []byte) error {
+	for _, b := range text {
+		if b < 0x20 {
+			return fmt.Errorf("text contains control code %#02x", b)
+		}
+	}
+	return nil
+}
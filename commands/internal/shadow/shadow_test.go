@@ -0,0 +1,125 @@
+package shadow
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/corrreia/govfd/types"
+)
+
+func TestWriteWrapsAcrossRows(t *testing.T) {
+	s := New(4, 2)
+	s.MoveTo(3, 1)
+	changed := s.Write([]byte("abcd"))
+	if !reflect.DeepEqual(changed, []int{1, 2}) {
+		t.Errorf("changed rows = %v, want [1 2]", changed)
+	}
+	if string(s.Line(1)) != "  ab" || string(s.Line(2)) != "cd  " {
+		t.Errorf("lines = %q, %q", s.Line(1), s.Line(2))
+	}
+	if col, row := s.Cursor(); col != 3 || row != 2 {
+		t.Errorf("cursor = (%d,%d), want (3,2)", col, row)
+	}
+}
+
+func TestWriteWrapsFromLastRowToFirst(t *testing.T) {
+	s := New(2, 2)
+	s.MoveTo(2, 2)
+	if changed := s.Write([]byte("xy")); !reflect.DeepEqual(changed, []int{2, 1}) {
+		t.Errorf("changed rows = %v, want [2 1]", changed)
+	}
+	if col, row := s.Cursor(); col != 2 || row != 1 {
+		t.Errorf("cursor = (%d,%d), want (2,1)", col, row)
+	}
+}
+
+func TestSetLinePadsAndCuts(t *testing.T) {
+	s := New(4, 1)
+	s.SetLine(1, []byte("ab"))
+	if string(s.Line(1)) != "ab  " {
+		t.Errorf("line = %q, want %q", s.Line(1), "ab  ")
+	}
+	s.SetLine(1, []byte("abcdef"))
+	if string(s.Line(1)) != "abcd" {
+		t.Errorf("line = %q, want %q", s.Line(1), "abcd")
+	}
+	if err := s.SetLine(2, nil); err == nil {
+		t.Error("SetLine(2) succeeded on a 1-row screen")
+	}
+}
+
+// testLines marks each line command with its row.
+type testLines struct{}
+
+func (testLines) Line(row int, text []byte) []byte {
+	return append([]byte{byte('0' + row)}, text...)
+}
+
+func TestLineProtocol(t *testing.T) {
+	var zero LineProtocol[testLines]
+	p, err := zero.Sized(&types.ModelProfile{Columns: 20, Rows: 2})
+	if err != nil {
+		t.Fatalf("Sized error: %v", err)
+	}
+	p.MoveCursor(19, 1)
+	p.Commit()
+	if got, _ := p.WriteText([]byte("abc")); string(got) != "1"+strings.Repeat(" ", 18)+"ab"+"2c"+strings.Repeat(" ", 19) {
+		t.Errorf("WriteText wrapping rows = %q", got)
+	}
+	if _, err := p.WriteText([]byte("a\rb")); err == nil {
+		t.Error("WriteText accepted a control code")
+	}
+	if _, err := p.SetBrightness(1); !errors.Is(err, types.ErrUnsupported) {
+		t.Errorf("SetBrightness error = %v, want ErrUnsupported", err)
+	}
+}
+
+func TestLineProtocolAppliesOnlyCommittedCommands(t *testing.T) {
+	var zero LineProtocol[testLines]
+	p, _ := zero.Sized(&types.ModelProfile{Columns: 4, Rows: 1})
+	p.WriteText([]byte("ab"))
+	p.Commit()
+	p.WriteText([]byte("cd")) // Not written to the display
+	if got, _ := p.WriteText([]byte("ef")); string(got) != "1abef" {
+		t.Errorf("after an uncommitted write got %q, want %q", got, "1abef")
+	}
+	if col, row := p.Cursor(); col != 3 || row != 1 {
+		t.Errorf("cursor = (%d,%d), want (3,1)", col, row)
+	}
+}
+
+func TestLineProtocolZeroValueKeepsNoState(t *testing.T) {
+	var p LineProtocol[testLines]
+	for range 2 {
+		p.WriteText([]byte("x"))
+		p.Commit()
+	}
+	if got, _ := p.WriteText([]byte("y")); string(got) != "1y"+strings.Repeat(" ", 19) {
+		t.Errorf("zero value wrote %q, want a blank screen with y", got)
+	}
+	if col, row := p.Cursor(); col != 0 || row != 0 {
+		t.Errorf("zero value cursor = (%d,%d), want unknown (0,0)", col, row)
+	}
+}
+
+func TestLineProtocolUploadRowRange(t *testing.T) {
+	var p LineProtocol[testLines]
+	one, err := p.Sized(&types.ModelProfile{Columns: 16, Rows: 1})
+	if err != nil {
+		t.Fatalf("Sized error: %v", err)
+	}
+	if got, err := one.UploadLine(1, []byte("ok")); err != nil || string(got) != "1ok"+strings.Repeat(" ", 14) {
+		t.Errorf("UploadLine(1) = %q, %v", got, err)
+	}
+	if _, err := one.UploadLine(2, []byte("no")); err == nil || !strings.Contains(err.Error(), "must be 1") {
+		t.Errorf("UploadLine(2) on one row: error = %v, want row must be 1", err)
+	}
+	if _, err := p.UploadLine(3, []byte("no")); err == nil || !strings.Contains(err.Error(), "between 1 and 2") {
+		t.Errorf("UploadLine(3) on two rows: error = %v, want row between 1 and 2", err)
+	}
+	if _, err := p.Sized(&types.ModelProfile{Columns: 20, Rows: 4}); err == nil {
+		t.Error("Sized accepted four rows")
+	}
+}
@@ -0,0 +1,100 @@
+// Package logiccontrols implements the native command set of Logic Controls
+// PD3000 and LD9000 series pole displays.
+package logiccontrols
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/corrreia/govfd/types"
+)
+
+// LogicControlsProtocol implements the Protocol interface for Logic Controls
+// displays. Cursor positions depend on the display width, so displays opened
+// for a model get an instance sized by ForModel; the zero value addresses
+// the usual 20x2 screen.
+type LogicControlsProtocol struct {
+	Columns int // Characters per row (20 if zero)
+	Rows    int // Rows (2 if zero)
+}
+
+// ForModel returns the protocol sized for the model's screen.
+func (p *LogicControlsProtocol) ForModel(profile *types.ModelProfile) (types.Protocol, error) {
+	if profile.Columns < 1 || profile.Rows < 1 || profile.Columns*profile.Rows > 256 {
+		return nil, fmt.Errorf("%dx%d screen cannot be addressed with one position byte", profile.Columns, profile.Rows)
+	}
+	return &LogicControlsProtocol{Columns: profile.Columns, Rows: profile.Rows}, nil
+}
+
+// size returns the screen geometry.
+func (p *LogicControlsProtocol) size() (columns, rows int) {
+	columns, rows = p.Columns, p.Rows
+	if columns == 0 {
+		columns = 20
+	}
+	if rows == 0 {
+		rows = 2
+	}
+	return columns, rows
+}
+
+// GetName returns the protocol name.
+func (p *LogicControlsProtocol) GetName() string {
+	return types.ProtocolLogicControls
+}
+
+// GetDescription returns the protocol description.
+func (p *LogicControlsProtocol) GetDescription() string {
+	return "Logic Controls PD3000/LD9000 native command set"
+}
+
+// Clear returns the command sequence to reset the display.
+func (p *LogicControlsProtocol) Clear() ([]byte, error) {
+	return SeqReset, nil
+}
+
+// FormFeed returns the command sequence to blank the screen while keeping
+// settings such as brightness: spaces over every position, then the cursor
+// back home. The display has no clear-screen command of its own.
+func (p *LogicControlsProtocol) FormFeed() ([]byte, error) {
+	columns, rows := p.size()
+	seq := make([]byte, 0, columns*rows+4)
+	seq = append(seq, BuildMoveCursorSeq(0)...)
+	for i := 0; i < columns*rows; i++ {
+		seq = append(seq, ' ')
+	}
+	return append(seq, BuildMoveCursorSeq(0)...), nil
+}
+
+// MoveCursor returns the command sequence to move cursor to position (1-based),
+// converted to the display's 0-based linear position.
+func (p *LogicControlsProtocol) MoveCursor(column, row int) ([]byte, error) {
+	columns, rows := p.size()
+	if column < 1 || column > columns || row < 1 || row > rows {
+		return nil, fmt.Errorf("column/row must be within %dx%d", columns, rows)
+	}
+	return BuildMoveCursorSeq(byte((row-1)*columns + column - 1)), nil
+}
+
+// SetBrightness returns the command sequence to set brightness level (1-4).
+func (p *LogicControlsProtocol) SetBrightness(level int) ([]byte, error) {
+	if level < 1 || level > len(BrightnessLevels) {
+		return nil, errors.New("brightness level must be between 1 and 4")
+	}
+	return BuildBrightnessSeq(BrightnessLevels[level-1]), nil
+}
+
+// SetBlink reports that Logic Controls displays have no cursor blink period.
+func (p *LogicControlsProtocol) SetBlink(intervalMs int) ([]byte, error) {
+	return nil, fmt.Errorf("cursor blink: %w", types.ErrUnsupported)
+}
+
+// SetCharset reports that the character set is fixed.
+func (p *LogicControlsProtocol) SetCharset(page int) ([]byte, error) {
+	return nil, fmt.Errorf("code table selection: %w", types.ErrUnsupported)
+}
+
+// SelfTest reports that there is no self-test command.
+func (p *LogicControlsProtocol) SelfTest() ([]byte, error) {
+	return nil, fmt.Errorf("self-test: %w", types.ErrUnsupported)
+}
@@ -0,0 +1,38 @@
+package logiccontrols
+
+// Logic Controls command byte constants. PD3000 and LD9000 series displays
+// use single-byte commands; the cursor is addressed by a 0-based position
+// running linearly across the rows.
+
+// Single-byte Commands
+const (
+	// Brightness (followed by a level byte)
+	CmdBrightness = 0x04
+
+	// Move cursor (followed by the 0-based linear position)
+	CmdMoveCursor = 0x10
+
+	// Reset - clears the display and restores power-on settings
+	CmdReset = 0x1F
+)
+
+// Brightness level bytes, dimmest first
+var BrightnessLevels = [...]byte{0x20, 0x40, 0x60, 0xFF}
+
+// Complete Command Sequences as byte arrays for convenience
+var (
+	// Reset display: 0x1F
+	SeqReset = []byte{CmdReset}
+)
+
+// BuildMoveCursorSeq creates the command sequence to move the cursor.
+// Returns: 0x10 position
+func BuildMoveCursorSeq(position byte) []byte {
+	return []byte{CmdMoveCursor, position}
+}
+
+// BuildBrightnessSeq creates the command sequence to set brightness.
+// Returns: 0x04 level
+func BuildBrightnessSeq(level byte) []byte {
+	return []byte{CmdBrightness, level}
+}
@@ -0,0 +1,76 @@
+// Package matrixorbital implements the 0xFE-prefixed command set of Matrix
+// Orbital LK/VK serial character modules and their clones.
+package matrixorbital
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/corrreia/govfd/types"
+)
+
+// MatrixOrbitalProtocol implements the Protocol interface for Matrix Orbital
+// modules.
+type MatrixOrbitalProtocol struct{}
+
+// GetName returns the protocol name.
+func (p *MatrixOrbitalProtocol) GetName() string {
+	return types.ProtocolMatrixOrbital
+}
+
+// GetDescription returns the protocol description.
+func (p *MatrixOrbitalProtocol) GetDescription() string {
+	return "Matrix Orbital 0xFE-prefixed command set"
+}
+
+// Clear returns the command sequence to clear the screen. The modules have
+// no initialize command; clearing also homes the cursor.
+func (p *MatrixOrbitalProtocol) Clear() ([]byte, error) {
+	return SeqClearScreen, nil
+}
+
+// FormFeed returns the command sequence to clear screen content.
+func (p *MatrixOrbitalProtocol) FormFeed() ([]byte, error) {
+	return SeqClearScreen, nil
+}
+
+// MoveCursor returns the command sequence to move cursor to position (1-based).
+func (p *MatrixOrbitalProtocol) MoveCursor(column, row int) ([]byte, error) {
+	if column < 1 || column > 255 || row < 1 || row > 255 {
+		return nil, errors.New("column/row must be between 1 and 255")
+	}
+	return BuildGotoSeq(byte(column), byte(row)), nil
+}
+
+// SetBrightness returns the command sequence to set brightness level (1-4).
+func (p *MatrixOrbitalProtocol) SetBrightness(level int) ([]byte, error) {
+	if level < 1 || level > len(BrightnessLevels) {
+		return nil, errors.New("brightness level must be between 1 and 4")
+	}
+	return BuildBrightnessSeq(BrightnessLevels[level-1]), nil
+}
+
+// SetBlink reports that the modules have no display blink command.
+func (p *MatrixOrbitalProtocol) SetBlink(intervalMs int) ([]byte, error) {
+	return nil, fmt.Errorf("display blink: %w", types.ErrUnsupported)
+}
+
+// SetCharset reports that the character ROM is fixed.
+func (p *MatrixOrbitalProtocol) SetCharset(page int) ([]byte, error) {
+	return nil, fmt.Errorf("code table %d: %w", page, types.ErrUnsupported)
+}
+
+// SelfTest reports that there is no self-test command.
+func (p *MatrixOrbitalProtocol) SelfTest() ([]byte, error) {
+	return nil, fmt.Errorf("self-test: %w", types.ErrUnsupported)
+}
+
+// WriteText returns text unchanged, rejecting the command prefix byte, which
+// the module would take as the start of a command.
+func (p *MatrixOrbitalProtocol) WriteText(text []byte) ([]byte, error) {
+	if i := bytes.IndexByte(text, CmdPrefix); i >= 0 {
+		return nil, fmt.Errorf("text byte %d is the command prefix %#02x", i, CmdPrefix)
+	}
+	return text, nil
+}
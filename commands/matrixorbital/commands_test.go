@@ -0,0 +1,48 @@
+package matrixorbital
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/corrreia/govfd/types"
+)
+
+func TestCommands(t *testing.T) {
+	p := &MatrixOrbitalProtocol{}
+
+	tests := []struct {
+		name  string
+		build func() ([]byte, error)
+		want  []byte
+	}{
+		{"Clear", p.Clear, []byte{0xFE, 0x58}},
+		{"MoveCursor", func() ([]byte, error) { return p.MoveCursor(40, 2) }, []byte{0xFE, 0x47, 40, 2}},
+		{"SetBrightness", func() ([]byte, error) { return p.SetBrightness(4) }, []byte{0xFE, 0x99, 0xFF}},
+	}
+	for _, tt := range tests {
+		got, err := tt.build()
+		if err != nil {
+			t.Fatalf("%s error: %v", tt.name, err)
+		}
+		if string(got) != string(tt.want) {
+			t.Errorf("%s = % X, want % X", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestInvalidArguments(t *testing.T) {
+	p := &MatrixOrbitalProtocol{}
+
+	if _, err := p.MoveCursor(0, 1); err == nil {
+		t.Error("MoveCursor(0, 1) succeeded")
+	}
+	if _, err := p.SetBrightness(0); err == nil {
+		t.Error("SetBrightness(0) succeeded")
+	}
+	if _, err := p.SetCharset(0); !errors.Is(err, types.ErrUnsupported) {
+		t.Errorf("SetCharset error = %v, want ErrUnsupported", err)
+	}
+	if _, err := p.WriteText([]byte{'a', 0xFE, 0x58}); err == nil {
+		t.Error("WriteText accepted the command prefix")
+	}
+}
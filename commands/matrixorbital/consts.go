@@ -0,0 +1,48 @@
+package matrixorbital
+
+// Matrix Orbital command byte constants. Every command starts with the 0xFE
+// prefix, so text must never contain that byte.
+
+// Command prefix
+const (
+	CmdPrefix = 0xFE
+)
+
+// Commands (0xFE + command)
+const (
+	// 0xFE G - Go to position (followed by column, row bytes)
+	CmdGoto = 0x47 // G
+
+	// 0xFE H - Go home
+	CmdHome = 0x48 // H
+
+	// 0xFE X - Clear screen and home the cursor
+	CmdClearScreen = 0x58 // X
+
+	// 0xFE 0x99 - Set brightness (followed by 0-255)
+	CmdBrightness = 0x99
+)
+
+// Brightness bytes for levels 1-4
+var BrightnessLevels = [...]byte{0x3F, 0x7F, 0xBF, 0xFF}
+
+// Complete Command Sequences as byte arrays for convenience
+var (
+	// Clear screen: 0xFE X
+	SeqClearScreen = []byte{CmdPrefix, CmdClearScreen}
+
+	// Go home: 0xFE H
+	SeqHome = []byte{CmdPrefix, CmdHome}
+)
+
+// BuildGotoSeq creates the command sequence to set cursor position.
+// Returns: 0xFE G column row
+func BuildGotoSeq(column, row byte) []byte {
+	return []byte{CmdPrefix, CmdGoto, column, row}
+}
+
+// BuildBrightnessSeq creates the command sequence to set brightness.
+// Returns: 0xFE 0x99 n
+func BuildBrightnessSeq(n byte) []byte {
+	return []byte{CmdPrefix, CmdBrightness, n}
+}
@@ -0,0 +1,86 @@
+// Package noritake implements the command set of Noritake Itron CU-U series
+// character VFD modules.
+package noritake
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/corrreia/govfd/types"
+)
+
+// NoritakeProtocol implements the Protocol interface for Noritake CU-U
+// modules.
+type NoritakeProtocol struct{}
+
+// GetName returns the protocol name.
+func (p *NoritakeProtocol) GetName() string {
+	return types.ProtocolNoritakeCU
+}
+
+// GetDescription returns the protocol description.
+func (p *NoritakeProtocol) GetDescription() string {
+	return "Noritake Itron CU-U series command set"
+}
+
+// Clear returns the command sequence to initialize the display.
+func (p *NoritakeProtocol) Clear() ([]byte, error) {
+	return SeqInitialize, nil
+}
+
+// FormFeed returns the command sequence to clear screen content.
+func (p *NoritakeProtocol) FormFeed() ([]byte, error) {
+	return SeqClearScreen, nil
+}
+
+// MoveCursor returns the command sequence to move cursor to position (1-based).
+func (p *NoritakeProtocol) MoveCursor(column, row int) ([]byte, error) {
+	if column < 1 || column > 255 || row < 1 || row > 255 {
+		return nil, errors.New("column/row must be between 1 and 255")
+	}
+	return BuildSetCursorSeq(byte(column), byte(row)), nil
+}
+
+// SetBrightness returns the command sequence to set luminance level (1-4).
+func (p *NoritakeProtocol) SetBrightness(level int) ([]byte, error) {
+	if level < 1 || level > len(LuminanceLevels) {
+		return nil, errors.New("brightness level must be between 1 and 4")
+	}
+	return BuildLuminanceSeq(LuminanceLevels[level-1]), nil
+}
+
+// SetBlink reports that CU-U modules have no cursor blink period.
+func (p *NoritakeProtocol) SetBlink(intervalMs int) ([]byte, error) {
+	return nil, fmt.Errorf("cursor blink: %w", types.ErrUnsupported)
+}
+
+// SetCharset returns the command sequence to set character encoding table.
+func (p *NoritakeProtocol) SetCharset(page int) ([]byte, error) {
+	if page < 0 || page > 255 {
+		return nil, errors.New("page must be between 0 and 255")
+	}
+	return BuildSetCharsetSeq(byte(page)), nil
+}
+
+// DefineGlyph returns the command sequence to download a custom font
+// character at code.
+func (p *NoritakeProtocol) DefineGlyph(code byte, glyph types.Glyph) ([]byte, error) {
+	if code < 0x20 {
+		return nil, fmt.Errorf("control code %#02x cannot hold a glyph", code)
+	}
+	return BuildDefineFontSeq(code, glyph), nil
+}
+
+// CancelGlyph returns the command sequence to delete the custom font
+// character at code.
+func (p *NoritakeProtocol) CancelGlyph(code byte) ([]byte, error) {
+	if code < 0x20 {
+		return nil, fmt.Errorf("control code %#02x cannot hold a glyph", code)
+	}
+	return BuildDeleteFontSeq(code), nil
+}
+
+// SelfTest reports that there is no self-test command.
+func (p *NoritakeProtocol) SelfTest() ([]byte, error) {
+	return nil, fmt.Errorf("self-test: %w", types.ErrUnsupported)
+}
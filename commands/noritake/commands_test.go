@@ -0,0 +1,58 @@
+package noritake
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/corrreia/govfd/types"
+)
+
+func TestNoritakeCommands(t *testing.T) {
+	p := &NoritakeProtocol{}
+
+	if got, err := p.MoveCursor(3, 2); err != nil || !bytes.Equal(got, []byte{0x1F, 0x24, 3, 2}) {
+		t.Errorf("MoveCursor(3, 2) = %x, %v", got, err)
+	}
+	if _, err := p.MoveCursor(0, 1); err == nil {
+		t.Error("MoveCursor(0, 1) should fail")
+	}
+	if got, err := p.SetBrightness(4); err != nil || !bytes.Equal(got, []byte{0x1B, 0x4C, LuminanceLevels[3]}) {
+		t.Errorf("SetBrightness(4) = %x, %v", got, err)
+	}
+	if _, err := p.SetBrightness(5); err == nil {
+		t.Error("SetBrightness(5) should fail")
+	}
+	if got, err := p.SetCharset(1); err != nil || !bytes.Equal(got, []byte{0x1B, 0x74, 1}) {
+		t.Errorf("SetCharset(1) = %x, %v", got, err)
+	}
+	if _, err := p.SetBlink(500); !errors.Is(err, types.ErrUnsupported) {
+		t.Errorf("SetBlink error = %v, want ErrUnsupported", err)
+	}
+}
+
+func TestNoritakeGlyphs(t *testing.T) {
+	p := &NoritakeProtocol{}
+	// Top row lit across, left column lit down.
+	glyph := types.Glyph{0x1F, 0x10, 0x10, 0x10, 0x10, 0x10, 0x10}
+
+	got, err := p.DefineGlyph(0x80, glyph)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []byte{0x1B, 0x26, 1, 0x80, 0x80, 5, 0xFE, 0x80, 0x80, 0x80, 0x80}
+	if !bytes.Equal(got, want) {
+		t.Errorf("DefineGlyph = % x, want % x", got, want)
+	}
+
+	if got, err := p.CancelGlyph(0x80); err != nil || !bytes.Equal(got, []byte{0x1B, 0x3F, 0x80}) {
+		t.Errorf("CancelGlyph = % x, %v", got, err)
+	}
+
+	if _, err := p.DefineGlyph(0x1F, glyph); err == nil {
+		t.Error("DefineGlyph at a control code should fail")
+	}
+	if _, err := p.CancelGlyph(0x0A); err == nil {
+		t.Error("CancelGlyph at a control code should fail")
+	}
+}
@@ -0,0 +1,95 @@
+package noritake
+
+import "github.com/corrreia/govfd/types"
+
+// Noritake Itron CU-U series command byte constants.
+
+// ASCII Control Characters
+const (
+	// Clear display (CLR)
+	CmdClearScreen = 0x0C
+
+	// Escape character - used as prefix for escape sequences
+	CmdEscape = 0x1B // ESC
+
+	// Unit Separator - used as prefix for cursor control
+	CmdUnitSeparator = 0x1F // US
+)
+
+// Escape Sequence Commands (ESC + command)
+const (
+	// ESC @ - Initialize display
+	CmdEscInitialize = 0x40 // @
+
+	// ESC L - Set luminance (followed by n)
+	CmdEscLuminance = 0x4C // L
+
+	// ESC t - Select character code table (followed by n)
+	CmdEscCharsetTable = 0x74 // t
+
+	// ESC & - Define custom font characters
+	CmdEscDefineFont = 0x26 // &
+
+	// ESC ? - Delete a custom font character
+	CmdEscDeleteFont = 0x3F // ?
+)
+
+// Unit Separator Commands (US + command)
+const (
+	// US $ - Set cursor position (followed by column, row bytes)
+	CmdUSSetCursor = 0x24 // $
+)
+
+// Luminance bytes for levels 1-4 (25%, 50%, 75%, 100%)
+var LuminanceLevels = [...]byte{0x00, 0x40, 0x80, 0xC0}
+
+// Complete Command Sequences as byte arrays for convenience
+var (
+	// Initialize display: ESC @
+	SeqInitialize = []byte{CmdEscape, CmdEscInitialize}
+
+	// Clear display: CLR
+	SeqClearScreen = []byte{CmdClearScreen}
+)
+
+// BuildSetCursorSeq creates the command sequence to set cursor position.
+// Returns: US $ column row
+func BuildSetCursorSeq(column, row byte) []byte {
+	return []byte{CmdUnitSeparator, CmdUSSetCursor, column, row}
+}
+
+// BuildLuminanceSeq creates the command sequence to set luminance.
+// Returns: ESC L n
+func BuildLuminanceSeq(n byte) []byte {
+	return []byte{CmdEscape, CmdEscLuminance, n}
+}
+
+// BuildSetCharsetSeq creates the command sequence to set character code table.
+// Returns: ESC t page
+func BuildSetCharsetSeq(page byte) []byte {
+	return []byte{CmdEscape, CmdEscCharsetTable, page}
+}
+
+// BuildDefineFontSeq creates the command sequence to define the custom font
+// character at code, one byte per column with the top dot in bit 7.
+// Returns: ESC & 1 code code 5 d1..d5
+func BuildDefineFontSeq(code byte, glyph types.Glyph) []byte {
+	seq := []byte{CmdEscape, CmdEscDefineFont, 1, code, code, types.GlyphWidth}
+	for col := 0; col < types.GlyphWidth; col++ {
+		var column byte
+		for row := 0; row < types.GlyphHeight; row++ {
+			if glyph[row]&(1<<(types.GlyphWidth-1-col)) != 0 {
+				column |= 0x80 >> row
+			}
+		}
+		seq = append(seq, column)
+	}
+	return seq
+}
+
+// BuildDeleteFontSeq creates the command sequence to delete the custom font
+// character at code, restoring the built-in character.
+// Returns: ESC ? code
+func BuildDeleteFontSeq(code byte) []byte {
+	return []byte{CmdEscape, CmdEscDeleteFont, code}
+}
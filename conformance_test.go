@@ -0,0 +1,98 @@
+package govfd
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/corrreia/govfd/commands/escpos"
+	"github.com/corrreia/govfd/protocoltest"
+	"github.com/corrreia/govfd/types"
+)
+
+// conformanceConfig describes what the protocol of profile should accept.
+func conformanceConfig(profile *types.ModelProfile) protocoltest.Config {
+	cfg := protocoltest.Config{Columns: profile.Columns, Rows: profile.Rows}
+	if profile.SupportsBrightness {
+		cfg.BrightnessLevels = profile.BrightnessLevels
+	}
+	if profile.SupportsCursorBlink {
+		cfg.BlinkIntervals = []int{0, 50, 500, 12750}
+	}
+	if profile.SupportsCharsetTable {
+		for _, page := range profile.CodePages {
+			cfg.CodePages = append(cfg.CodePages, page)
+		}
+		slices.Sort(cfg.CodePages)
+	}
+	return cfg
+}
+
+// escposDecoder decodes ESC/POS commands back into protocol calls with the
+// escpos stream decoder.
+var escposDecoder = protocoltest.DecoderFunc(func(cmd []byte) (protocoltest.Call, error) {
+	decoded := escpos.NewDecoder().Decode(cmd)
+	if len(decoded) == 0 {
+		return protocoltest.Call{}, ErrUnsupported
+	}
+	call := func(method string, args ...int) (protocoltest.Call, error) {
+		return protocoltest.Call{Method: method, Args: args}, nil
+	}
+	switch c := decoded[0].Command.(type) {
+	case escpos.Initialize:
+		return call("Clear")
+	case escpos.ClearScreen:
+		return call("FormFeed")
+	case escpos.MoveCursor:
+		return call("MoveCursor", c.Column, c.Row)
+	case escpos.SetBrightness:
+		return call("SetBrightness", c.Level)
+	case escpos.SetBlink:
+		return call("SetBlink", c.IntervalMs)
+	case escpos.SelectCodeTable:
+		return call("SetCharset", c.Page)
+	case escpos.SelfTest:
+		return call("SelfTest")
+	case escpos.SetClock:
+		return call("SetClock", c.Hour, c.Minute)
+	case escpos.ShowClock:
+		return call("ShowClock")
+	case escpos.DefineGlyphs:
+		return call("DefineGlyph", int(c.First))
+	case escpos.CancelGlyph:
+		return call("CancelGlyph", int(c.Code))
+	}
+	return protocoltest.Call{}, ErrUnsupported
+})
+
+func TestBuiltInProtocolsConform(t *testing.T) {
+	// One model of every built-in protocol, at each geometry it comes in
+	models := []types.Model{
+		types.ModelEpsonDMD110,
+		types.ModelEpsonDMD110Japanese,
+		types.ModelGenericCD5220,
+		types.ModelGenericAedex,
+		types.ModelGenericDSP800,
+		types.ModelWincorBA63,
+		types.ModelWincorBA66,
+		types.ModelLogicControlsPD3000,
+		types.ModelNoritakeCU20025,
+		types.ModelNoritakeCU20045,
+		types.ModelMatrixOrbitalLK162,
+		types.ModelMatrixOrbitalLK204,
+		types.ModelMatrixOrbitalLK402,
+	}
+	for _, model := range models {
+		t.Run(string(model), func(t *testing.T) {
+			profile := mustProfile(t, model)
+			protocol, err := protocolForModel(profile)
+			if err != nil {
+				t.Fatalf("protocolForModel error: %v", err)
+			}
+			cfg := conformanceConfig(profile)
+			if profile.CommandProtocol == types.ProtocolESCPOS {
+				cfg.Decoder = escposDecoder
+			}
+			protocoltest.Run(t, protocol, cfg)
+		})
+	}
+}
@@ -17,17 +17,14 @@ func (d *Display) SetCursor(column, row int) error {
 	if d.rows > 0 && row > d.rows {
 		return errors.New("row exceeds configured height")
 	}
-	if column > 255 || row > 255 {
-		return errors.New("column/row out of device range")
-	}
 	if d.cursorColumn == column && d.cursorRow == row {
 		return nil
 	}
-	cmd := d.protocol.MoveCursor(column, row)
-	if cmd == nil {
-		return errors.New("invalid cursor position for this protocol")
+	cmd, err := d.protocol.MoveCursor(column, row)
+	if err != nil {
+		return err
 	}
-	if err := d.writeBytes(cmd); err != nil {
+	if err := d.writeCommand(cmd); err != nil {
 		return err
 	}
 	d.cursorColumn = column
@@ -5,6 +5,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/corrreia/govfd/commands/escpos"
+	"github.com/corrreia/govfd/types"
+
 	"go.bug.st/serial"
 )
 
@@ -23,16 +26,16 @@ func (m *mockPort) Write(p []byte) (int, error) {
 	m.written = append(m.written, p...)
 	return len(p), nil
 }
-func (m *mockPort) ResetInputBuffer() error                          { return nil }
-func (m *mockPort) ResetOutputBuffer() error                         { return nil }
-func (m *mockPort) SetDTR(dtr bool) error                            { return nil }
-func (m *mockPort) SetRTS(rts bool) error                            { return nil }
-func (m *mockPort) SetReadTimeout(t time.Duration) error             { return nil }
+func (m *mockPort) ResetInputBuffer() error                              { return nil }
+func (m *mockPort) ResetOutputBuffer() error                             { return nil }
+func (m *mockPort) SetDTR(dtr bool) error                                { return nil }
+func (m *mockPort) SetRTS(rts bool) error                                { return nil }
+func (m *mockPort) SetReadTimeout(t time.Duration) error                 { return nil }
 func (m *mockPort) GetModemStatusBits() (*serial.ModemStatusBits, error) { return nil, nil }
-func (m *mockPort) Close() error                                     { return nil }
-func (m *mockPort) Break(t time.Duration) error                      { return nil }
-func (m *mockPort) Drain() error                                     { return nil }
-func (m *mockPort) SetMode(mode *serial.Mode) error                  { return nil }
+func (m *mockPort) Close() error                                         { return nil }
+func (m *mockPort) Break(t time.Duration) error                          { return nil }
+func (m *mockPort) Drain() error                                         { return nil }
+func (m *mockPort) SetMode(mode *serial.Mode) error                      { return nil }
 
 // newTestDisplay creates a Display with a mock serial port and protocol for testing.
 func newTestDisplay(cols, rows int) (*Display, *mockPort) {
@@ -176,3 +179,28 @@ func TestAdvanceCursorNoDimensionsIsNoOp(t *testing.T) {
 		t.Errorf("cursor = (%d,%d) without dimensions set, want (1,1) unchanged", col, row)
 	}
 }
+
+func TestWriteTextFullWidthAdvancesTwoColumns(t *testing.T) {
+	d, port := newTestDisplay(20, 2)
+	encoder := escpos.NewCharsetEncoder()
+	if err := encoder.SetDoubleByteCharset(types.DoubleByteGB2312); err != nil {
+		t.Fatalf("SetDoubleByteCharset: %v", err)
+	}
+	d.encoder = encoder
+	d.SetCursor(1, 1)
+	port.written = nil
+
+	if err := d.WriteText("中文A"); err != nil {
+		t.Fatalf("WriteText error: %v", err)
+	}
+
+	// FS & precedes the text; two full-width characters plus one ASCII = 5 columns
+	want := append(append([]byte{}, escpos.SeqKanjiModeOn...), 0xD6, 0xD0, 0xCE, 0xC4, 'A')
+	if string(port.written) != string(want) {
+		t.Errorf("wrote % X, want % X", port.written, want)
+	}
+	col, row := d.GetCursor()
+	if col != 6 || row != 1 {
+		t.Errorf("cursor = (%d,%d), want (6,1)", col, row)
+	}
+}
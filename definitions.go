@@ -0,0 +1,65 @@
+package govfd
+
+import (
+	"errors"
+
+	"github.com/corrreia/govfd/commands/declarative"
+	"github.com/corrreia/govfd/types"
+)
+
+// Declarative protocols return ErrUnsupported for the commands their
+// definition leaves out.
+var (
+	_ ReverseProtocol     = (*declarative.DeclarativeProtocol)(nil)
+	_ DisplayModeProtocol = (*declarative.DeclarativeProtocol)(nil)
+	_ ClockProtocol       = (*declarative.DeclarativeProtocol)(nil)
+)
+
+// RegisterProtocolDefinition builds the protocol described by def and
+// registers it together with the models the definition lists, so a display
+// that differs from supported ones only in its command bytes needs no Go code.
+// Either the protocol and all its models are registered, or none of them.
+func RegisterProtocolDefinition(def *declarative.Definition) error {
+	protocol, err := declarative.New(def)
+	if err != nil {
+		return err
+	}
+	profiles := make(map[types.Model]*types.ModelProfile, len(def.Models))
+	for _, m := range def.Models {
+		profile := m.Profile(def)
+		if err := validateModel(m.ID, &profile); err != nil {
+			return err
+		}
+		profiles[m.ID] = &profile
+	}
+
+	// Hold both registries so nothing is registered unless everything is.
+	commandRegistryMu.Lock()
+	defer commandRegistryMu.Unlock()
+	modelRegistryMu.Lock()
+	defer modelRegistryMu.Unlock()
+	name := protocol.GetName()
+	if _, exists := commandRegistry[name]; exists {
+		return errors.New("protocol already registered: " + name)
+	}
+	for _, m := range def.Models {
+		if _, exists := modelRegistry[m.ID]; exists {
+			return errors.New("model already registered: " + string(m.ID))
+		}
+	}
+	commandRegistry[name] = protocol
+	for model, profile := range profiles {
+		modelRegistry[model] = profile
+	}
+	return nil
+}
+
+// LoadProtocolDefinition reads a protocol definition from a JSON or YAML
+// file and registers it with RegisterProtocolDefinition.
+func LoadProtocolDefinition(path string) error {
+	def, err := declarative.LoadFile(path)
+	if err != nil {
+		return err
+	}
+	return RegisterProtocolDefinition(def)
+}
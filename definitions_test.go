@@ -0,0 +1,116 @@
+package govfd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/corrreia/govfd/commands/declarative"
+	"github.com/corrreia/govfd/types"
+)
+
+func TestLoadProtocolDefinition(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pole.yaml")
+	err := os.WriteFile(path, []byte(`
+name: TEST-DEFINED
+commands:
+  clear: "1B 40"
+  move_cursor: "10 {col} {row}"
+parameters:
+  col: {offset: -1}
+  row: {offset: -1}
+models:
+  - id: TEST_DEFINED_20X2
+    columns: 20
+    rows: 2
+`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadProtocolDefinition(path); err != nil {
+		t.Fatalf("LoadProtocolDefinition error: %v", err)
+	}
+	if err := LoadProtocolDefinition(path); err == nil {
+		t.Error("loading the same definition twice succeeded")
+	}
+
+	protocol, ok := GetProtocol("TEST-DEFINED")
+	if !ok {
+		t.Fatal("protocol not registered")
+	}
+	d, port := newTestDisplay(20, 2)
+	d.protocol = protocol
+	d.encoder, err = newModelEncoder(protocol, mustProfile(t, "TEST_DEFINED_20X2"))
+	if err != nil {
+		t.Fatalf("newModelEncoder error: %v", err)
+	}
+
+	if err := d.SetCursor(3, 2); err != nil {
+		t.Fatalf("SetCursor error: %v", err)
+	}
+	if err := d.WriteText("Øre"); err != nil {
+		t.Fatalf("WriteText error: %v", err)
+	}
+	if want := "\x10\x02\x01?re"; string(port.written) != want {
+		t.Errorf("wrote % X, want % X", port.written, want)
+	}
+	if err := d.SetBrightness(2); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("SetBrightness error = %v, want ErrUnsupported", err)
+	}
+}
+
+func mustProfile(t *testing.T, model types.Model) *types.ModelProfile {
+	t.Helper()
+	profile, ok := GetModelProfile(model)
+	if !ok {
+		t.Fatalf("model %s not registered", model)
+	}
+	return profile
+}
+
+func TestExampleDefinitionIsValid(t *testing.T) {
+	def, err := declarative.LoadFile("examples/definitions/generic-pole.yaml")
+	if err != nil {
+		t.Fatalf("LoadFile error: %v", err)
+	}
+	if _, err := declarative.New(def); err != nil {
+		t.Errorf("example definition invalid: %v", err)
+	}
+}
+
+func TestRegisterProtocolDefinitionIsAllOrNothing(t *testing.T) {
+	definition := func(ids ...types.Model) *declarative.Definition {
+		def := &declarative.Definition{
+			Name:     "TEST-ATOMIC",
+			Commands: map[string]string{declarative.CommandClear: "0C"},
+		}
+		for _, id := range ids {
+			def.Models = append(def.Models, declarative.ModelDefinition{ID: id, Columns: 20, Rows: 2})
+		}
+		return def
+	}
+
+	// A duplicate model ID is rejected before anything is registered
+	if err := RegisterProtocolDefinition(definition("TEST_ATOMIC_A", "TEST_ATOMIC_A")); err == nil {
+		t.Error("definition with a duplicate model registered")
+	}
+	// So is a model ID that is already taken
+	if err := RegisterProtocolDefinition(definition("TEST_ATOMIC_A", types.ModelEpsonDMD110)); err == nil {
+		t.Error("definition reusing a registered model registered")
+	}
+	if _, ok := GetProtocol("TEST-ATOMIC"); ok {
+		t.Error("protocol registered by a failed definition")
+	}
+	if IsModelSupported("TEST_ATOMIC_A") {
+		t.Error("model registered by a failed definition")
+	}
+
+	// The corrected definition can then be registered
+	if err := RegisterProtocolDefinition(definition("TEST_ATOMIC_A", "TEST_ATOMIC_B")); err != nil {
+		t.Fatalf("retry error: %v", err)
+	}
+	if !IsModelSupported("TEST_ATOMIC_A") || !IsModelSupported("TEST_ATOMIC_B") {
+		t.Error("models not registered by the retry")
+	}
+}
@@ -7,11 +7,22 @@ import (
 )
 
 // Clear sends ESC @ to initialize/clear the display state.
+// Initialization also restores the default code table and leaves Kanji mode.
 func (d *Display) Clear() error {
 	if d.protocol == nil {
 		return errors.New("no command protocol set")
 	}
-	return d.writeBytes(d.protocol.Clear())
+	cmd, err := d.protocol.Clear()
+	if err != nil {
+		return err
+	}
+	if err := d.writeCommand(cmd); err != nil {
+		return err
+	}
+	if d.encoder != nil {
+		d.encoder.Reset()
+	}
+	return nil
 }
 
 // FormFeed sends a form feed (0x0C) to clear the screen.
@@ -19,37 +30,54 @@ func (d *Display) FormFeed() error {
 	if d.protocol == nil {
 		return errors.New("no command protocol set")
 	}
-	return d.writeBytes(d.protocol.FormFeed())
+	cmd, err := d.protocol.FormFeed()
+	if err != nil {
+		return err
+	}
+	return d.writeCommand(cmd)
 }
 
 // WriteText writes a string to the display at the current cursor position.
 // Character encoding is handled automatically — just send UTF-8 text.
+// Under a strict or transliterate encoding policy, text with characters the
+// policy rejects yields an *EncodingError and nothing is sent.
 func (d *Display) WriteText(message string) error {
 	if d.protocol == nil {
 		return errors.New("no command protocol set")
 	}
+	if err := d.checkEncodingPolicy(message); err != nil {
+		return err
+	}
 
-	encodedBytes, err := d.smartEncodeText(message)
+	encodedBytes, cells, err := d.smartEncodeText(message)
 	if err != nil {
 		return err
 	}
 
-	if err := d.writeBytes(encodedBytes); err != nil {
+	// Protocols that cannot take plain text wrap it in their own commands.
+	if protocol, ok := d.protocol.(TextProtocol); ok {
+		if encodedBytes, err = protocol.WriteText(encodedBytes); err != nil {
+			return err
+		}
+	}
+	if err := d.writeCommand(encodedBytes); err != nil {
 		return err
 	}
-	// All encoding paths produce single-byte-per-character output,
-	// so len(encodedBytes) equals the display character count.
-	d.advanceCursorBy(len(encodedBytes))
+	// The encoder counts the columns: with the default encoder every byte
+	// is one column, full-width characters in Kanji mode being two bytes.
+	d.advanceCursorBy(cells)
 	return nil
 }
 
 // smartEncodeText encodes UTF-8 text for the display's active charset,
-// switching charsets automatically when needed.
-func (d *Display) smartEncodeText(text string) ([]byte, error) {
+// switching charsets automatically when needed. It also returns the number
+// of display columns the text occupies.
+func (d *Display) smartEncodeText(text string) ([]byte, int, error) {
 	if d.encoder == nil {
-		return escpos.SanitizeForDisplay(text), nil
+		encoded := escpos.SanitizeForDisplay(text)
+		return encoded, len(encoded), nil
 	}
-	return d.encoder.EncodeTextWithAutoCharsetSwitching(text, d)
+	return d.encoder.Encode(text, d.switcher())
 }
 
 // WriteRawBytes writes raw bytes directly to the display at the current cursor position.
@@ -63,17 +91,15 @@ func (d *Display) WriteRawBytes(data []byte) error {
 }
 
 // SetBrightness sets the display brightness (expected 1..4 on many VFDs).
-// This uses the command sequence US X n.
+// This uses the command sequence US X n. The protocol rejects levels
+// outside its range.
 func (d *Display) SetBrightness(level int) error {
 	if d.protocol == nil {
 		return errors.New("no command protocol set")
 	}
-	if level < 1 || level > 4 {
-		return errors.New("brightness level must be between 1 and 4")
-	}
-	cmd := d.protocol.SetBrightness(level)
-	if cmd == nil {
-		return errors.New("invalid brightness level for this protocol")
+	cmd, err := d.protocol.SetBrightness(level)
+	if err != nil {
+		return err
 	}
 	if err := d.writeBytes(cmd); err != nil {
 		return err
@@ -96,9 +122,9 @@ func (d *Display) SetBlink(ms int) error {
 	if ms < 0 {
 		return errors.New("blink ms must be >= 0")
 	}
-	cmd := d.protocol.SetBlink(ms)
-	if cmd == nil {
-		return errors.New("invalid blink interval for this protocol")
+	cmd, err := d.protocol.SetBlink(ms)
+	if err != nil {
+		return err
 	}
 	if err := d.writeBytes(cmd); err != nil {
 		return err
@@ -124,30 +150,9 @@ func (d *Display) SelfTest() error {
 	if d.protocol == nil {
 		return errors.New("no command protocol set")
 	}
-	return d.writeBytes(d.protocol.SelfTest())
-}
-
-// SetCharacterCodeTableInternal selects the character code table page.
-// This implements the escpos.CharsetSwitcher interface and is called
-// automatically by the encoding system — do not call directly.
-func (d *Display) SetCharacterCodeTableInternal(page int) error {
-	if d.protocol == nil {
-		return errors.New("no command protocol set")
-	}
-	if page < 0 || page > 255 {
-		return errors.New("page must be between 0 and 255")
-	}
-	cmd := d.protocol.SetCharset(page)
-	if cmd == nil {
-		return errors.New("invalid charset page for this protocol")
-	}
-
-	// Write to hardware first — only update encoder if the write succeeds.
-	if err := d.writeBytes(cmd); err != nil {
+	cmd, err := d.protocol.SelfTest()
+	if err != nil {
 		return err
 	}
-	if d.encoder != nil {
-		d.encoder.SetCharset(page)
-	}
-	return nil
+	return d.writeBytes(cmd)
 }
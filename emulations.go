@@ -0,0 +1,178 @@
+package govfd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"maps"
+
+	"github.com/corrreia/govfd/types"
+)
+
+// GetModelEmulations returns the command protocols a model can run, its
+// default first.
+func GetModelEmulations(model types.Model) ([]string, bool) {
+	profile, exists := GetModelProfile(model)
+	if !exists {
+		return nil, false
+	}
+	emulations := modelEmulations(profile)
+	protocols := make([]string, 0, len(emulations))
+	protocols = append(protocols, profile.CommandProtocol)
+	for _, emulation := range emulations {
+		if emulation.Protocol != profile.CommandProtocol {
+			protocols = append(protocols, emulation.Protocol)
+		}
+	}
+	return protocols, true
+}
+
+// SelectEmulation switches the display to another of its model's emulations
+// by sending the emulation's select command. The display's encoder is
+// replaced by one for the new emulation, GetProfile reports the emulation's
+// capabilities and the cursor becomes unknown. It returns an error wrapping
+// ErrUnsupported if only DIP switches or the setup menu select the emulation.
+func (d *Display) SelectEmulation(protocolName string) error {
+	if d.profile == nil {
+		return errors.New("display was not opened for a model")
+	}
+	emulation, err := modelEmulation(d.profile, protocolName)
+	if err != nil {
+		return err
+	}
+	if emulation.Select == nil {
+		return fmt.Errorf("selecting emulation %s on model %s by command: %w",
+			protocolName, d.profile.Name, ErrUnsupported)
+	}
+	profile := emulationProfile(d.profile, emulation)
+	protocol, err := protocolForModel(profile)
+	if err != nil {
+		return err
+	}
+	encoder, err := newModelEncoder(protocol, profile)
+	if err != nil {
+		return err
+	}
+	if err := d.writeBytes(emulation.Select); err != nil {
+		return err
+	}
+	d.protocol = protocol
+	d.encoder = encoder
+	d.cursorColumn, d.cursorRow = 0, 0
+	return nil
+}
+
+// GetProtocol returns the command protocol the display is driven with.
+func (d *Display) GetProtocol() Protocol {
+	return d.protocol
+}
+
+// GetProfile returns a copy of the profile of the display's model at its
+// opened size, set up for the running emulation: its CommandProtocol, code
+// tables and capabilities. Capabilities the emulation's protocol lacks, such
+// as brightness under AEDEX, are turned off. It reports false for displays
+// opened with Open.
+func (d *Display) GetProfile() (*types.ModelProfile, bool) {
+	if d.profile == nil {
+		return nil, false
+	}
+	profile := d.profile
+	if emulation, err := modelEmulation(d.profile, d.protocol.GetName()); err == nil {
+		profile = emulationProfile(d.profile, emulation)
+	}
+	profile = cloneModelProfile(profile)
+	limitCapabilities(profile, d.protocol)
+	return profile, true
+}
+
+// modelEmulations returns the emulations of profile. A profile without a
+// list runs its CommandProtocol only.
+func modelEmulations(profile *types.ModelProfile) []types.Emulation {
+	if profile.Emulations == nil {
+		return []types.Emulation{{Protocol: profile.CommandProtocol}}
+	}
+	return profile.Emulations
+}
+
+// modelEmulation returns the emulation of profile running protocolName, or
+// the default emulation if protocolName is empty.
+func modelEmulation(profile *types.ModelProfile, protocolName string) (types.Emulation, error) {
+	if protocolName == "" {
+		protocolName = profile.CommandProtocol
+	}
+	for _, emulation := range modelEmulations(profile) {
+		if emulation.Protocol == protocolName {
+			return emulation, nil
+		}
+	}
+	return types.Emulation{}, errors.New("model " + profile.Name + " does not support protocol " + protocolName)
+}
+
+// emulationProfile returns a copy of profile set up for emulation.
+func emulationProfile(profile *types.ModelProfile, emulation types.Emulation) *types.ModelProfile {
+	emulated := *profile
+	emulated.CommandProtocol = emulation.Protocol
+	if emulation.CodePages != nil {
+		emulated.CodePages = emulation.CodePages
+	}
+	return &emulated
+}
+
+// limitCapabilities turns off the capabilities of profile that protocol
+// reports as unsupported. The profile describes the hardware; an emulation
+// may not reach all of it.
+func limitCapabilities(profile *types.ModelProfile, protocol Protocol) {
+	unsupported := func(_ []byte, err error) bool {
+		return errors.Is(err, ErrUnsupported)
+	}
+	if unsupported(protocol.SetBrightness(1)) {
+		profile.SupportsBrightness = false
+		profile.BrightnessLevels = 0
+	}
+	if unsupported(protocol.SetBlink(0)) {
+		profile.SupportsCursorBlink = false
+	}
+	if unsupported(protocol.SetCharset(0)) {
+		profile.SupportsCharsetTable = false
+	}
+	if unsupported(protocol.SelfTest()) {
+		profile.SupportsSelfTest = false
+	}
+}
+
+// validateEmulations checks that a profile's emulations name distinct
+// protocols and include its default.
+func validateEmulations(profile *types.ModelProfile) error {
+	if profile.Emulations == nil {
+		return nil
+	}
+	seen := make(map[string]bool, len(profile.Emulations))
+	for _, emulation := range profile.Emulations {
+		if emulation.Protocol == "" {
+			return errors.New("emulation protocol is required")
+		}
+		if seen[emulation.Protocol] {
+			return errors.New("duplicate emulation: " + emulation.Protocol)
+		}
+		seen[emulation.Protocol] = true
+	}
+	if !seen[profile.CommandProtocol] {
+		return errors.New("emulations do not include the default protocol " + profile.CommandProtocol)
+	}
+	return nil
+}
+
+// cloneEmulations returns a copy of emulations that shares no select
+// commands or code tables with it.
+func cloneEmulations(emulations []types.Emulation) []types.Emulation {
+	if emulations == nil {
+		return nil
+	}
+	cloned := make([]types.Emulation, len(emulations))
+	for i, emulation := range emulations {
+		emulation.Select = bytes.Clone(emulation.Select)
+		emulation.CodePages = maps.Clone(emulation.CodePages)
+		cloned[i] = emulation
+	}
+	return cloned
+}
@@ -0,0 +1,220 @@
+package govfd
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/corrreia/govfd/types"
+)
+
+// switchableProfile is a display that switches from ESC/POS to CD5220 or
+// DSP800 by command, but to Aedex only by DIP switch.
+func switchableProfile() types.ModelProfile {
+	return types.ModelProfile{
+		Name:                 "Switchable 20x2",
+		Columns:              20,
+		Rows:                 2,
+		CommandProtocol:      types.ProtocolESCPOS,
+		SupportsBrightness:   true,
+		BrightnessLevels:     4,
+		SupportsCharsetTable: true,
+		CodePages:            types.CodePageTable{types.CodePagePC437: 0, types.CodePagePC858: 19},
+		Emulations: []types.Emulation{
+			{Protocol: types.ProtocolESCPOS, Select: []byte{0x02, 0x05, 0x43, 0x31, 0x03}},
+			{
+				Protocol:  types.ProtocolCD5220,
+				Select:    []byte{0x02, 0x05, 0x43, 0x32, 0x03},
+				CodePages: types.CodePageTable{types.CodePagePC437: 0},
+			},
+			{Protocol: types.ProtocolDSP800, Select: []byte{0x02, 0x05, 0x43, 0x33, 0x03}},
+			{Protocol: types.ProtocolAedex},
+		},
+	}
+}
+
+func TestGetModelEmulations(t *testing.T) {
+	protocols, ok := GetModelEmulations(types.ModelGenericMultiEmulation)
+	want := []string{types.ProtocolESCPOS, types.ProtocolCD5220, types.ProtocolDSP800, types.ProtocolAedex}
+	if !ok || !slices.Equal(protocols, want) {
+		t.Errorf("multi-emulation display: got %v, %v; want %v", protocols, ok, want)
+	}
+
+	protocols, ok = GetModelEmulations(types.ModelEpsonDMD110)
+	if !ok || !slices.Equal(protocols, []string{types.ProtocolESCPOS}) {
+		t.Errorf("DM-D110: got %v, %v; want only ESC/POS", protocols, ok)
+	}
+	if _, ok := GetModelEmulations("NO_SUCH_MODEL"); ok {
+		t.Error("found emulations of an unknown model")
+	}
+}
+
+func TestRegisterModelEmulations(t *testing.T) {
+	profile := switchableProfile()
+	if err := RegisterModel("TEST_SWITCHABLE", &profile); err != nil {
+		t.Fatalf("RegisterModel error: %v", err)
+	}
+	profile.Emulations[1].Select[3] = 'X' // The registry keeps its own copy
+
+	registered, _ := GetModelProfile("TEST_SWITCHABLE")
+	if got := registered.Emulations[1].Select[3]; got != 0x32 {
+		t.Errorf("registered select command changed to %q with the caller's", got)
+	}
+
+	noDefault := switchableProfile()
+	noDefault.Emulations = noDefault.Emulations[1:]
+	duplicate := switchableProfile()
+	duplicate.Emulations = append(duplicate.Emulations, types.Emulation{Protocol: types.ProtocolAedex})
+	unnamed := switchableProfile()
+	unnamed.Emulations = append(unnamed.Emulations, types.Emulation{})
+
+	invalid := map[string]*types.ModelProfile{
+		"TEST_NODEFAULT": &noDefault,
+		"TEST_DUPLICATE": &duplicate,
+		"TEST_UNNAMED":   &unnamed,
+	}
+	for model, profile := range invalid {
+		if err := RegisterModel(types.Model(model), profile); err == nil {
+			t.Errorf("%s: RegisterModel succeeded", model)
+		}
+	}
+}
+
+func TestSelectEmulation(t *testing.T) {
+	profile := switchableProfile()
+	d, port := newTestDisplay(20, 2)
+	d.profile = &profile
+	d.SetCursor(1, 1)
+	port.written = nil
+
+	if err := d.SelectEmulation(types.ProtocolCD5220); err != nil {
+		t.Fatalf("SelectEmulation error: %v", err)
+	}
+	if want := "\x02\x05C2\x03"; string(port.written) != want {
+		t.Errorf("wrote % X, want % X", port.written, want)
+	}
+	if name := d.GetProtocol().GetName(); name != types.ProtocolCD5220 {
+		t.Errorf("protocol = %s, want %s", name, types.ProtocolCD5220)
+	}
+	if col, row := d.GetCursor(); col != 0 || row != 0 {
+		t.Errorf("cursor = (%d,%d) after switching, want unknown (0,0)", col, row)
+	}
+
+	// Commands now come from CD5220, text from the emulation's code tables
+	port.written = nil
+	d.SetCursor(1, 2)
+	d.WriteText("5€")
+	if want := "\x1bl\x01\x02" + "5EUR"; string(port.written) != want {
+		t.Errorf("wrote %q, want %q", port.written, want)
+	}
+
+	if err := d.SelectEmulation(types.ProtocolESCPOS); err != nil {
+		t.Fatalf("SelectEmulation back to the default error: %v", err)
+	}
+	if name := d.GetProtocol().GetName(); name != types.ProtocolESCPOS {
+		t.Errorf("protocol = %s, want %s", name, types.ProtocolESCPOS)
+	}
+}
+
+func TestSelectEmulationCapabilities(t *testing.T) {
+	profile := switchableProfile()
+	d, _ := newTestDisplay(20, 2)
+	d.profile = &profile
+
+	check := func(protocol string, brightness bool, levels int, charsetTable bool) {
+		t.Helper()
+		got, ok := d.GetProfile()
+		if !ok {
+			t.Fatal("GetProfile reported no profile")
+		}
+		if got.CommandProtocol != protocol || got.SupportsBrightness != brightness ||
+			got.BrightnessLevels != levels || got.SupportsCharsetTable != charsetTable {
+			t.Errorf("%s: profile = %s, brightness %v/%d, code tables %v; want brightness %v/%d, code tables %v",
+				protocol, got.CommandProtocol, got.SupportsBrightness, got.BrightnessLevels,
+				got.SupportsCharsetTable, brightness, levels, charsetTable)
+		}
+	}
+	check(types.ProtocolESCPOS, true, 4, true)
+
+	// DSP800 has neither brightness nor code table commands
+	if err := d.SelectEmulation(types.ProtocolDSP800); err != nil {
+		t.Fatalf("SelectEmulation error: %v", err)
+	}
+	check(types.ProtocolDSP800, false, 0, false)
+	if err := d.SetBrightness(2); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("SetBrightness under DSP800: error = %v, want ErrUnsupported", err)
+	}
+
+	// The model's own capabilities come back with the default emulation
+	if err := d.SelectEmulation(types.ProtocolESCPOS); err != nil {
+		t.Fatalf("SelectEmulation back to the default error: %v", err)
+	}
+	check(types.ProtocolESCPOS, true, 4, true)
+	if !profile.SupportsBrightness || profile.Emulations[2].Select[3] != 0x33 {
+		t.Error("GetProfile changed the display's model profile")
+	}
+
+	if _, ok := (&Display{}).GetProfile(); ok {
+		t.Error("GetProfile reported a profile for a display opened without a model")
+	}
+}
+
+func TestModelProfilesMatchTheirProtocols(t *testing.T) {
+	for _, model := range GetSupportedModels() {
+		d, _ := newModelTestDisplay(t, model)
+		d.profile = mustProfile(t, model)
+		got, _ := d.GetProfile()
+		want := d.profile
+		if got.SupportsBrightness != want.SupportsBrightness || got.BrightnessLevels != want.BrightnessLevels ||
+			got.SupportsCursorBlink != want.SupportsCursorBlink || got.SupportsCharsetTable != want.SupportsCharsetTable ||
+			got.SupportsSelfTest != want.SupportsSelfTest {
+			t.Errorf("%s: profile declares capabilities its protocol %s lacks", model, want.CommandProtocol)
+		}
+	}
+}
+
+func TestBuiltInMultiEmulationNeedsDIPSwitches(t *testing.T) {
+	d, port := newModelTestDisplay(t, types.ModelGenericMultiEmulation)
+	d.profile = mustProfile(t, types.ModelGenericMultiEmulation)
+
+	if err := d.SelectEmulation(types.ProtocolAedex); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("SelectEmulation error = %v, want ErrUnsupported", err)
+	}
+	if len(port.written) != 0 {
+		t.Errorf("wrote % X without a select command", port.written)
+	}
+}
+
+func TestSelectEmulationErrors(t *testing.T) {
+	profile := switchableProfile()
+	d, port := newTestDisplay(20, 2)
+	d.profile = &profile
+
+	if err := d.SelectEmulation(types.ProtocolAedex); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("DIP-switch emulation: error = %v, want ErrUnsupported", err)
+	}
+	if err := d.SelectEmulation(types.ProtocolBA63); err == nil {
+		t.Error("switched to an emulation the model lacks")
+	}
+	if len(port.written) != 0 {
+		t.Errorf("failed switches wrote % X", port.written)
+	}
+	if name := d.GetProtocol().GetName(); name != types.ProtocolESCPOS {
+		t.Errorf("protocol = %s after failed switches, want %s", name, types.ProtocolESCPOS)
+	}
+
+	d.profile = nil
+	if err := d.SelectEmulation(types.ProtocolCD5220); err == nil {
+		t.Error("switched emulation on a display not opened for a model")
+	}
+}
+
+func TestOpenModelRejectsUnsupportedProtocol(t *testing.T) {
+	// The choice is checked before the port is opened
+	_, err := OpenModelWithOptions("/dev/govfd-test-missing", types.ModelEpsonDMD110,
+		&Options{Protocol: types.ProtocolCD5220})
+	if err == nil || !strings.Contains(err.Error(), "does not support protocol") {
+		t.Errorf("error = %v, want unsupported protocol", err)
+	}
+}
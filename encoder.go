@@ -0,0 +1,175 @@
+package govfd
+
+import (
+	"errors"
+
+	"github.com/corrreia/govfd/commands/escpos"
+	"github.com/corrreia/govfd/types"
+)
+
+// Encoder turns UTF-8 text into the bytes a display shows. It tracks which
+// character sets are active on the display and changes them through a
+// types.CharsetSwitcher, which builds the commands with the display's protocol.
+//
+// Protocols without their own encoder get the code table encoder of the
+// escpos package; a protocol can supply one by implementing EncoderProvider.
+type Encoder interface {
+	// Encode encodes text for the display's current state, first sending
+	// any character set changes it needs through s. It returns the bytes and
+	// the number of display cells they occupy.
+	Encode(text string, s types.CharsetSwitcher) ([]byte, int, error)
+
+	// Measure describes how Encode would treat text without changing the
+	// encoder's state. The commands Encode would send go to s.
+	Measure(text string, s types.CharsetSwitcher) (*types.Measurement, error)
+
+	// Reset restores the state the display has after initialization.
+	Reset()
+}
+
+// EncoderProvider is implemented by protocols that supply their own Encoder
+// for a model instead of the default code table encoder.
+type EncoderProvider interface {
+	NewEncoder(profile *types.ModelProfile) (Encoder, error)
+}
+
+// GlyphEncoder is implemented by encoders that draw runes from user-defined
+// glyphs downloaded on demand.
+type GlyphEncoder interface {
+	Encoder
+	// DefineGlyph registers glyph for r and returns the slot r occupies, if any.
+	DefineGlyph(r rune, glyph types.Glyph) (byte, bool, error)
+	// GlyphSlots returns which rune occupies each downloaded glyph slot.
+	GlyphSlots() map[byte]rune
+}
+
+// CharsetPolicyEncoder is implemented by encoders that choose between code
+// tables according to a types.CharsetPolicy.
+type CharsetPolicyEncoder interface {
+	Encoder
+	SetCharsetPolicy(policy types.CharsetPolicy) error
+	CharsetPolicy() types.CharsetPolicy
+	// Plan selects one code table covering all of texts up front.
+	Plan(texts []string, s types.CharsetSwitcher) error
+}
+
+// ApproximationEncoder is implemented by encoders that show stand-ins for
+// symbols the display cannot show.
+type ApproximationEncoder interface {
+	Encoder
+	SetApproximation(r rune, replacement string) error
+}
+
+// InvalidUTF8Encoder is implemented by encoders that can be told how to
+// treat text that is not valid UTF-8.
+type InvalidUTF8Encoder interface {
+	Encoder
+	SetInvalidUTF8Policy(policy types.InvalidUTF8Policy) error
+}
+
+// The default encoder supports every optional encoder interface.
+var (
+	_ GlyphEncoder         = (*escpos.CharsetEncoder)(nil)
+	_ CharsetPolicyEncoder = (*escpos.CharsetEncoder)(nil)
+	_ ApproximationEncoder = (*escpos.CharsetEncoder)(nil)
+	_ InvalidUTF8Encoder   = (*escpos.CharsetEncoder)(nil)
+)
+
+// SetEncoder replaces the display's text encoder, e.g. with one tailored to
+// a protocol or firmware. The encoder should match the display's current
+// state; Clear resets both.
+func (d *Display) SetEncoder(encoder Encoder) error {
+	if encoder == nil {
+		return errors.New("encoder is nil")
+	}
+	d.encoder = encoder
+	return nil
+}
+
+// GetEncoder returns the display's text encoder.
+func (d *Display) GetEncoder() Encoder {
+	return d.encoder
+}
+
+// newModelEncoder creates the character encoder for a model: the protocol's
+// own, if it provides one, or else a code table encoder restricted to the
+// code tables and double-byte charset declared in the model profile.
+func newModelEncoder(protocol Protocol, profile *types.ModelProfile) (Encoder, error) {
+	if provider, ok := protocol.(EncoderProvider); ok {
+		encoder, err := provider.NewEncoder(profile)
+		if err != nil {
+			return nil, errors.New("model " + profile.Name + ": " + err.Error())
+		}
+		return encoder, nil
+	}
+	encoder := escpos.NewCharsetEncoder()
+	if profile.CodePages != nil {
+		if err := encoder.SetCodePages(profile.CodePages); err != nil {
+			return nil, errors.New("model " + profile.Name + ": " + err.Error())
+		}
+	}
+	if err := encoder.SetDoubleByteCharset(profile.DoubleByteCharset); err != nil {
+		return nil, errors.New("model " + profile.Name + ": " + err.Error())
+	}
+	if _, ok := protocol.(InternationalCharsetProtocol); !ok {
+		encoder.SetInternationalSwitching(false)
+	}
+	return encoder, nil
+}
+
+// protocolSwitcher implements types.CharsetSwitcher by building each command
+// with a protocol and handing the bytes to send: the serial port for
+// WriteText, a buffer for Measure.
+type protocolSwitcher struct {
+	protocol Protocol
+	send     func(cmd []byte) error
+}
+
+// sendCommand sends the command built by the protocol, if it could build one.
+func (s *protocolSwitcher) sendCommand(cmd []byte, err error) error {
+	if err != nil {
+		return err
+	}
+	return s.send(cmd)
+}
+
+func (s *protocolSwitcher) SelectCodeTable(page int) error {
+	return s.sendCommand(s.protocol.SetCharset(page))
+}
+
+func (s *protocolSwitcher) SetDoubleByteMode(enabled bool) error {
+	protocol, ok := s.protocol.(DoubleByteProtocol)
+	if !ok {
+		return unsupported(s.protocol, "double-byte mode")
+	}
+	return s.sendCommand(protocol.SetDoubleByteMode(enabled))
+}
+
+func (s *protocolSwitcher) SelectInternationalCharset(charset types.InternationalCharset) error {
+	protocol, ok := s.protocol.(InternationalCharsetProtocol)
+	if !ok {
+		return unsupported(s.protocol, "international charset "+string(charset))
+	}
+	return s.sendCommand(protocol.SetInternationalCharset(charset))
+}
+
+func (s *protocolSwitcher) DefineGlyph(code byte, glyph types.Glyph) error {
+	protocol, ok := s.protocol.(GlyphProtocol)
+	if !ok {
+		return unsupported(s.protocol, "user-defined characters")
+	}
+	return s.sendCommand(protocol.DefineGlyph(code, glyph))
+}
+
+func (s *protocolSwitcher) CancelGlyph(code byte) error {
+	protocol, ok := s.protocol.(GlyphProtocol)
+	if !ok {
+		return unsupported(s.protocol, "user-defined characters")
+	}
+	return s.sendCommand(protocol.CancelGlyph(code))
+}
+
+// switcher returns the CharsetSwitcher that sends commands to the display.
+func (d *Display) switcher() *protocolSwitcher {
+	return &protocolSwitcher{protocol: d.protocol, send: d.writeBytes}
+}
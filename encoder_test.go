@@ -0,0 +1,107 @@
+package govfd
+
+import (
+	"testing"
+	"unicode/utf8"
+
+	"github.com/corrreia/govfd/commands/escpos"
+	"github.com/corrreia/govfd/models/epson"
+	"github.com/corrreia/govfd/types"
+)
+
+// utf8Encoder stands in for the encoder of a display that takes UTF-8
+// directly: bytes and cells differ, and it selects the Japan international
+// set once to exercise the switcher.
+type utf8Encoder struct {
+	selected bool
+	resets   int
+}
+
+func (e *utf8Encoder) Encode(text string, s types.CharsetSwitcher) ([]byte, int, error) {
+	if !e.selected {
+		if err := s.SelectInternationalCharset(types.InternationalJapan); err != nil {
+			return nil, 0, err
+		}
+		e.selected = true
+	}
+	return []byte(text), utf8.RuneCountInString(text), nil
+}
+
+func (e *utf8Encoder) Measure(text string, s types.CharsetSwitcher) (*types.Measurement, error) {
+	return &types.Measurement{Cells: utf8.RuneCountInString(text)}, nil
+}
+
+func (e *utf8Encoder) Reset() {
+	e.selected = false
+	e.resets++
+}
+
+func TestCustomEncoderCountsCells(t *testing.T) {
+	d, port := newTestDisplay(20, 2)
+	encoder := &utf8Encoder{}
+	if err := d.SetEncoder(encoder); err != nil {
+		t.Fatalf("SetEncoder: %v", err)
+	}
+	d.SetCursor(1, 1)
+	port.written = nil
+
+	if err := d.WriteText("ação"); err != nil {
+		t.Fatalf("WriteText error: %v", err)
+	}
+	want := append([]byte{0x1B, 0x52, 0x08}, "ação"...)
+	if string(port.written) != string(want) {
+		t.Errorf("written = % X, want % X", port.written, want)
+	}
+	if col, _ := d.GetCursor(); col != 5 {
+		t.Errorf("cursor column = %d, want 5 (4 cells, not %d bytes)", col, len("ação"))
+	}
+
+	if err := d.Clear(); err != nil {
+		t.Fatalf("Clear error: %v", err)
+	}
+	if encoder.resets != 1 {
+		t.Errorf("encoder reset %d times by Clear, want 1", encoder.resets)
+	}
+}
+
+func TestCustomEncoderWithoutOptionalFeatures(t *testing.T) {
+	d, _ := newTestDisplay(20, 2)
+	d.SetEncoder(&utf8Encoder{})
+
+	if err := d.DefineGlyph('✓', types.Glyph{}); err == nil {
+		t.Error("DefineGlyph succeeded on an encoder without glyph support")
+	}
+	if err := d.SetCharsetPolicy(types.CharsetPolicy{Locale: "pt"}); err == nil {
+		t.Error("SetCharsetPolicy succeeded on an encoder without charset policies")
+	}
+	if err := d.SetEncoder(nil); err == nil {
+		t.Error("SetEncoder(nil) succeeded")
+	}
+}
+
+// providingProtocol is ESC/POS with its own encoder.
+type providingProtocol struct {
+	escpos.ESCPOSProtocol
+}
+
+func (p *providingProtocol) NewEncoder(profile *types.ModelProfile) (Encoder, error) {
+	return &utf8Encoder{}, nil
+}
+
+func TestProtocolSuppliesEncoder(t *testing.T) {
+	encoder, err := newModelEncoder(&providingProtocol{}, &epson.DMD110Profile)
+	if err != nil {
+		t.Fatalf("newModelEncoder: %v", err)
+	}
+	if _, ok := encoder.(*utf8Encoder); !ok {
+		t.Errorf("encoder = %T, want the protocol's *utf8Encoder", encoder)
+	}
+
+	encoder, err = newModelEncoder(&escpos.ESCPOSProtocol{}, &epson.DMD110Profile)
+	if err != nil {
+		t.Fatalf("newModelEncoder: %v", err)
+	}
+	if _, ok := encoder.(*escpos.CharsetEncoder); !ok {
+		t.Errorf("encoder = %T, want the default *escpos.CharsetEncoder", encoder)
+	}
+}
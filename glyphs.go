@@ -0,0 +1,44 @@
+package govfd
+
+import (
+	"errors"
+
+	"github.com/corrreia/govfd/types"
+)
+
+// DefineGlyph registers a custom 5x7 glyph for r. Whenever text contains r
+// and none of the model's code tables has it, the glyph is downloaded into a
+// free user-defined character slot (evicting the least recently used glyph
+// if all are taken) and that slot's code is sent in place of r.
+//
+// If r already occupies a slot, the new bitmap is downloaded immediately.
+func (d *Display) DefineGlyph(r rune, glyph types.Glyph) error {
+	if d.protocol == nil {
+		return errors.New("no command protocol set")
+	}
+	if _, ok := d.protocol.(GlyphProtocol); !ok {
+		return unsupported(d.protocol, "user-defined characters")
+	}
+	encoder, ok := d.encoder.(GlyphEncoder)
+	if !ok {
+		return errors.New("character encoder does not support user-defined glyphs")
+	}
+	code, downloaded, err := encoder.DefineGlyph(r, glyph)
+	if err != nil {
+		return err
+	}
+	if downloaded {
+		return d.switcher().DefineGlyph(code, glyph)
+	}
+	return nil
+}
+
+// GetGlyphSlots returns which rune's glyph currently occupies each
+// user-defined character slot, keyed by character code.
+func (d *Display) GetGlyphSlots() map[byte]rune {
+	encoder, ok := d.encoder.(GlyphEncoder)
+	if !ok {
+		return map[byte]rune{}
+	}
+	return encoder.GlyphSlots()
+}
@@ -0,0 +1,53 @@
+package govfd
+
+import (
+	"testing"
+
+	"github.com/corrreia/govfd/commands/escpos"
+	"github.com/corrreia/govfd/types"
+)
+
+func TestWriteTextDownloadsGlyph(t *testing.T) {
+	d, port := newTestDisplay(20, 2)
+	d.encoder = escpos.NewCharsetEncoder()
+	d.SetCursor(1, 1)
+	port.written = nil
+
+	arrow := types.Glyph{0b00100, 0b00010, 0b11111, 0b00010, 0b00100, 0, 0}
+	if err := d.DefineGlyph('→', arrow); err != nil {
+		t.Fatalf("DefineGlyph error: %v", err)
+	}
+	if len(port.written) != 0 {
+		t.Fatalf("DefineGlyph wrote % X before the glyph was needed", port.written)
+	}
+
+	if err := d.WriteText("A→B"); err != nil {
+		t.Fatalf("WriteText error: %v", err)
+	}
+
+	slots := d.GetGlyphSlots()
+	if len(slots) != 1 {
+		t.Fatalf("glyph slots = %v, want one", slots)
+	}
+	var code byte
+	for c := range slots {
+		code = c
+	}
+	want := append(escpos.BuildDefineGlyphSeq(code, arrow), 'A', code, 'B')
+	if string(port.written) != string(want) {
+		t.Errorf("wrote % X, want % X", port.written, want)
+	}
+	col, row := d.GetCursor()
+	if col != 4 || row != 1 {
+		t.Errorf("cursor = (%d,%d), want (4,1)", col, row)
+	}
+
+	// Redefining a downloaded glyph sends the new bitmap right away
+	port.written = nil
+	if err := d.DefineGlyph('→', types.Glyph{}); err != nil {
+		t.Fatalf("DefineGlyph error: %v", err)
+	}
+	if want := escpos.BuildDefineGlyphSeq(code, types.Glyph{}); string(port.written) != string(want) {
+		t.Errorf("redefine wrote % X, want % X", port.written, want)
+	}
+}
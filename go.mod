@@ -7,6 +7,7 @@ toolchain go1.24.0
 require (
 	go.bug.st/serial v1.6.4
 	golang.org/x/text v0.28.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
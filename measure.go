@@ -0,0 +1,55 @@
+package govfd
+
+import (
+	"errors"
+
+	"github.com/corrreia/govfd/types"
+)
+
+// Measure reports how text would look on a freshly initialized display of the
+// given model: the columns it occupies, the code tables it needs, the switch
+// commands that would precede it and the runes that would be transliterated
+// or replaced. No serial port is needed.
+func Measure(model types.Model, text string) (*types.Measurement, error) {
+	profile, exists := GetModelProfile(model)
+	if !exists {
+		return nil, errors.New("unsupported VFD model: " + string(model))
+	}
+	protocol, err := protocolForModel(profile)
+	if err != nil {
+		return nil, err
+	}
+	encoder, err := newModelEncoder(protocol, profile)
+	if err != nil {
+		return nil, err
+	}
+	return measureText(protocol, encoder, text)
+}
+
+// Measure reports how text would look if written now with WriteText, given
+// the display's current charset state. Nothing is sent to the device.
+func (d *Display) Measure(text string) (*types.Measurement, error) {
+	if d.protocol == nil {
+		return nil, errors.New("no command protocol set")
+	}
+	if d.encoder == nil {
+		return nil, errors.New("no character encoder set")
+	}
+	return measureText(d.protocol, d.encoder, text)
+}
+
+// measureText runs the encoder's dry run on text, collecting the commands
+// it would send, so the exact WriteText logic decides the result.
+func measureText(protocol Protocol, encoder Encoder, text string) (*types.Measurement, error) {
+	var switches []byte
+	switcher := &protocolSwitcher{protocol: protocol, send: func(cmd []byte) error {
+		switches = append(switches, cmd...)
+		return nil
+	}}
+	report, err := encoder.Measure(text, switcher)
+	if err != nil {
+		return nil, err
+	}
+	report.Switches = switches
+	return report, nil
+}
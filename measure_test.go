@@ -0,0 +1,109 @@
+package govfd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/corrreia/govfd/commands/escpos"
+	"github.com/corrreia/govfd/types"
+)
+
+func TestMeasureCodePageSwitch(t *testing.T) {
+	m, err := Measure(types.ModelEpsonDMD110, "ação")
+	if err != nil {
+		t.Fatalf("Measure error: %v", err)
+	}
+	if m.Cells != 4 {
+		t.Errorf("Cells = %d, want 4", m.Cells)
+	}
+	if !reflect.DeepEqual(m.CodePages, []types.CodePage{types.CodePagePC860}) {
+		t.Errorf("CodePages = %v, want [PC860]", m.CodePages)
+	}
+	if want := escpos.BuildSetCharsetSeq(3); string(m.Switches) != string(want) {
+		t.Errorf("Switches = % X, want % X", m.Switches, want)
+	}
+	if len(m.Substitutions) != 0 {
+		t.Errorf("Substitutions = %v, want none", m.Substitutions)
+	}
+}
+
+func TestMeasureSubstitutions(t *testing.T) {
+	m, err := Measure(types.ModelEpsonDMD110, "ガム 350")
+	if err != nil {
+		t.Fatalf("Measure error: %v", err)
+	}
+	// ｶﾞ + ﾑ + " 350"
+	if m.Cells != 7 {
+		t.Errorf("Cells = %d, want 7", m.Cells)
+	}
+	want := []types.Substitution{
+		{Offset: 0, Rune: 'ガ', Replacement: "ｶﾞ"},
+		{Offset: 3, Rune: 'ム', Replacement: "ﾑ"},
+	}
+	if !reflect.DeepEqual(m.Substitutions, want) {
+		t.Errorf("Substitutions = %+v, want %+v", m.Substitutions, want)
+	}
+	if !reflect.DeepEqual(m.CodePages, []types.CodePage{types.CodePageKatakana}) {
+		t.Errorf("CodePages = %v, want [KATAKANA]", m.CodePages)
+	}
+
+	m, err = Measure(types.ModelEpsonDMD110, "a日b")
+	if err != nil {
+		t.Fatalf("Measure error: %v", err)
+	}
+	want = []types.Substitution{{Offset: 1, Rune: '日', Replacement: "?", Replaced: true}}
+	if !reflect.DeepEqual(m.Substitutions, want) {
+		t.Errorf("Substitutions = %+v, want %+v", m.Substitutions, want)
+	}
+}
+
+func TestMeasureASCIINeedsNoCodePage(t *testing.T) {
+	m, err := Measure(types.ModelEpsonDMD110, "Total 9.99")
+	if err != nil {
+		t.Fatalf("Measure error: %v", err)
+	}
+	if m.Cells != 10 || len(m.CodePages) != 0 || len(m.Switches) != 0 {
+		t.Errorf("got %+v, want 10 cells, no code pages, no switches", m)
+	}
+}
+
+func TestMeasureDoubleByte(t *testing.T) {
+	m, err := Measure(types.ModelEpsonDMD110SimplifiedChinese, "中文")
+	if err != nil {
+		t.Fatalf("Measure error: %v", err)
+	}
+	if !m.DoubleByte || m.Cells != 4 {
+		t.Errorf("DoubleByte = %v, Cells = %d, want true, 4", m.DoubleByte, m.Cells)
+	}
+	if string(m.Switches) != string(escpos.SeqKanjiModeOn) {
+		t.Errorf("Switches = % X, want % X", m.Switches, escpos.SeqKanjiModeOn)
+	}
+}
+
+func TestDisplayMeasureSendsNothing(t *testing.T) {
+	d, port := newTestDisplay(20, 2)
+	d.encoder = escpos.NewCharsetEncoder()
+
+	m, err := d.Measure("€19.99")
+	if err != nil {
+		t.Fatalf("Measure error: %v", err)
+	}
+	if len(port.written) != 0 {
+		t.Errorf("Measure wrote % X, want nothing", port.written)
+	}
+	if len(m.Switches) == 0 {
+		t.Error("Switches empty, want ESC t for PC858")
+	}
+
+	// The real write still switches: Measure did not change the display state
+	d.WriteText("€19.99")
+	if string(port.written[:len(m.Switches)]) != string(m.Switches) {
+		t.Errorf("WriteText sent % X, want it to start with % X", port.written, m.Switches)
+	}
+}
+
+func TestMeasureUnknownModel(t *testing.T) {
+	if _, err := Measure("NO_SUCH_MODEL", "x"); err == nil {
+		t.Fatal("expected error for unknown model, got nil")
+	}
+}
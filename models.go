@@ -1,7 +1,17 @@
 package govfd
 
 import (
+	"errors"
+	"maps"
+	"slices"
+	"sync"
+
 	"github.com/corrreia/govfd/models/epson"
+	"github.com/corrreia/govfd/models/generic"
+	"github.com/corrreia/govfd/models/logiccontrols"
+	"github.com/corrreia/govfd/models/matrixorbital"
+	"github.com/corrreia/govfd/models/noritake"
+	"github.com/corrreia/govfd/models/wincor"
 	"github.com/corrreia/govfd/types"
 
 	"go.bug.st/serial"
@@ -17,37 +27,116 @@ type Options struct {
 	// If zero, bounds are not enforced beyond device limits (1..255).
 	Columns int
 	Rows    int
+	// Optional command protocol, one of the model's emulations.
+	// If empty, the model's default CommandProtocol is used.
+	Protocol string
 }
 
-// modelRegistry contains profiles for all supported VFD models.
-var modelRegistry = map[types.Model]*types.ModelProfile{
-	types.ModelEpsonDMD110: &epson.DMD110Profile,
+var (
+	// modelRegistry contains profiles for all supported VFD models.
+	modelRegistry = map[types.Model]*types.ModelProfile{
+		types.ModelEpsonDMD110:                   &epson.DMD110Profile,
+		types.ModelEpsonDMD110SimplifiedChinese:  &epson.DMD110SimplifiedChineseProfile,
+		types.ModelEpsonDMD110TraditionalChinese: &epson.DMD110TraditionalChineseProfile,
+		types.ModelEpsonDMD110Korean:             &epson.DMD110KoreanProfile,
+		types.ModelEpsonDMD110Japanese:           &epson.DMD110JapaneseProfile,
+		types.ModelGenericCD5220:                 &generic.CD5220Profile,
+		types.ModelGenericAedex:                  &generic.AedexProfile,
+		types.ModelGenericDSP800:                 &generic.DSP800Profile,
+		types.ModelGenericMultiEmulation:         &generic.MultiEmulationProfile,
+		types.ModelWincorBA63:                    &wincor.BA63Profile,
+		types.ModelWincorBA66:                    &wincor.BA66Profile,
+		types.ModelLogicControlsPD3000:           &logiccontrols.PD3000Profile,
+		types.ModelLogicControlsLD9000:           &logiccontrols.LD9000Profile,
+		types.ModelNoritakeCU20025:               &noritake.CU20025Profile,
+		types.ModelNoritakeCU20045:               &noritake.CU20045Profile,
+		types.ModelMatrixOrbitalLK162:            &matrixorbital.LK162Profile,
+		types.ModelMatrixOrbitalLK204:            &matrixorbital.LK204Profile,
+		types.ModelMatrixOrbitalLK402:            &matrixorbital.LK402Profile,
+	}
+	modelRegistryMu sync.RWMutex
+)
+
+// RegisterModel adds a model profile, so the model can be opened with
+// OpenModel. The profile is copied. Its CommandProtocol need not be
+// registered yet, but must be by the time the model is opened. It is safe to
+// call concurrently; registering a model twice is an error.
+func RegisterModel(model types.Model, profile *types.ModelProfile) error {
+	if err := validateModel(model, profile); err != nil {
+		return err
+	}
+	registered := cloneModelProfile(profile)
+	modelRegistryMu.Lock()
+	defer modelRegistryMu.Unlock()
+	if _, exists := modelRegistry[model]; exists {
+		return errors.New("model already registered: " + string(model))
+	}
+	modelRegistry[model] = registered
+	return nil
 }
 
-// GetModelProfile returns the profile for the specified model.
+// validateModel checks a model and its profile before registration.
+func validateModel(model types.Model, profile *types.ModelProfile) error {
+	if model == "" {
+		return errors.New("model identifier is required")
+	}
+	if profile == nil {
+		return errors.New("model profile is nil")
+	}
+	if profile.Columns < 1 || profile.Rows < 1 {
+		return errors.New("model " + string(model) + ": columns and rows must be >= 1")
+	}
+	if profile.CommandProtocol == "" {
+		return errors.New("model " + string(model) + ": command protocol is required")
+	}
+	if err := validateEmulations(profile); err != nil {
+		return errors.New("model " + string(model) + ": " + err.Error())
+	}
+	return nil
+}
+
+// GetModelProfile returns a copy of the profile for the specified model;
+// changing it does not affect the registered model.
 func GetModelProfile(model types.Model) (*types.ModelProfile, bool) {
+	modelRegistryMu.RLock()
+	defer modelRegistryMu.RUnlock()
 	profile, exists := modelRegistry[model]
-	return profile, exists
+	if !exists {
+		return nil, false
+	}
+	return cloneModelProfile(profile), true
+}
+
+// cloneModelProfile returns a copy of profile that shares no code tables,
+// emulations or select commands with it.
+func cloneModelProfile(profile *types.ModelProfile) *types.ModelProfile {
+	cloned := *profile
+	cloned.CodePages = maps.Clone(profile.CodePages)
+	cloned.Emulations = cloneEmulations(profile.Emulations)
+	return &cloned
 }
 
-// GetSupportedModels returns a list of all supported VFD models.
+// GetSupportedModels returns all supported VFD models, sorted.
 func GetSupportedModels() []types.Model {
+	modelRegistryMu.RLock()
+	defer modelRegistryMu.RUnlock()
 	models := make([]types.Model, 0, len(modelRegistry))
 	for model := range modelRegistry {
 		models = append(models, model)
 	}
+	slices.Sort(models)
 	return models
 }
 
 // IsModelSupported checks if a model is supported by this library.
 func IsModelSupported(model types.Model) bool {
-	_, exists := modelRegistry[model]
+	_, exists := GetModelProfile(model)
 	return exists
 }
 
 // GetModelSpecs returns the basic specifications for a model.
 func GetModelSpecs(model types.Model) (columns, rows int, found bool) {
-	if profile, exists := modelRegistry[model]; exists {
+	if profile, exists := GetModelProfile(model); exists {
 		return profile.Columns, profile.Rows, true
 	}
 	return 0, 0, false
@@ -55,7 +144,7 @@ func GetModelSpecs(model types.Model) (columns, rows int, found bool) {
 
 // GetModelDefaults returns default serial communication settings for a model.
 func GetModelDefaults(model types.Model) (*Options, bool) {
-	profile, exists := modelRegistry[model]
+	profile, exists := GetModelProfile(model)
 	if !exists {
 		return nil, false
 	}
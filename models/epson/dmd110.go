@@ -1,6 +1,8 @@
 package epson
 
 import (
+	"maps"
+
 	"github.com/corrreia/govfd/types"
 
 	"go.bug.st/serial"
@@ -23,5 +25,38 @@ var DMD110Profile = types.ModelProfile{
 	SupportsCursorBlink:  true,
 	SupportsCharsetTable: true,
 	SupportsSelfTest:     true,
-	DocumentationURL:     "https://download4.epson.biz/sec_pubs/pos/reference_en/escpos_dm/commands.html",
+	CodePages: types.CodePageTable{
+		types.CodePagePC437:    0,
+		types.CodePageKatakana: 1,
+		types.CodePagePC850:    2,
+		types.CodePagePC860:    3,
+		types.CodePagePC858:    19,
+	},
+	DocumentationURL: "https://download4.epson.biz/sec_pubs/pos/reference_en/escpos_dm/commands.html",
+}
+
+// DMD110SimplifiedChineseProfile contains the specification for the Simplified
+// Chinese DM-D110, which accepts GB2312 double-byte characters.
+var DMD110SimplifiedChineseProfile = dmd110Variant("Simplified Chinese", types.DoubleByteGB2312)
+
+// DMD110TraditionalChineseProfile contains the specification for the Traditional
+// Chinese DM-D110, which accepts Big5 double-byte characters.
+var DMD110TraditionalChineseProfile = dmd110Variant("Traditional Chinese", types.DoubleByteBig5)
+
+// DMD110KoreanProfile contains the specification for the Korean DM-D110,
+// which accepts KS C 5601 double-byte characters.
+var DMD110KoreanProfile = dmd110Variant("Korean", types.DoubleByteKSC5601)
+
+// DMD110JapaneseProfile contains the specification for the Japanese DM-D110,
+// which accepts Shift-JIS double-byte characters.
+var DMD110JapaneseProfile = dmd110Variant("Japanese", types.DoubleByteShiftJIS)
+
+// dmd110Variant derives an Asian variant profile from DMD110Profile, with a
+// code page table of its own.
+func dmd110Variant(variant string, charset types.DoubleByteCharset) types.ModelProfile {
+	profile := DMD110Profile
+	profile.CodePages = maps.Clone(DMD110Profile.CodePages)
+	profile.Name = DMD110Profile.Name + " (" + variant + ")"
+	profile.DoubleByteCharset = charset
+	return profile
 }
@@ -0,0 +1,32 @@
+package generic
+
+import (
+	"github.com/corrreia/govfd/types"
+
+	"go.bug.st/serial"
+)
+
+// CD5220Profile contains the specification for a generic 20x2 pole display
+// in CD5220 mode. Code table numbering varies by firmware, so only the
+// power-on PC437 table is declared; register a profile listing the others
+// if your display has them.
+var CD5220Profile = types.ModelProfile{
+	Name:                 "Generic CD5220 Pole Display",
+	Manufacturer:         "Generic",
+	Model:                "CD5220",
+	Columns:              20,
+	Rows:                 2,
+	DefaultBaudRate:      9600,
+	DefaultDataBits:      8,
+	DefaultParity:        serial.NoParity,
+	DefaultStopBits:      serial.OneStopBit,
+	CommandProtocol:      types.ProtocolCD5220,
+	SupportsBrightness:   true,
+	BrightnessLevels:     4,
+	SupportsCursorBlink:  false,
+	SupportsCharsetTable: true,
+	SupportsSelfTest:     false,
+	CodePages: types.CodePageTable{
+		types.CodePagePC437: 0,
+	},
+}
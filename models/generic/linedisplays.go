@@ -0,0 +1,37 @@
+package generic
+
+import (
+	"github.com/corrreia/govfd/types"
+
+	"go.bug.st/serial"
+)
+
+// AedexProfile contains the specification for a generic 20x2 pole display
+// in Aedex mode. Aedex only replaces whole lines; cursor addressing is
+// emulated.
+var AedexProfile = lineDisplay("Aedex", types.ProtocolAedex)
+
+// DSP800Profile contains the specification for a generic 20x2 pole display
+// in DSP800 mode. DSP800 only replaces whole lines; cursor addressing is
+// emulated.
+var DSP800Profile = lineDisplay("DSP800", types.ProtocolDSP800)
+
+// lineDisplay returns the profile of a 20x2 display with a fixed PC437
+// character set and no brightness control.
+func lineDisplay(mode, protocol string) types.ModelProfile {
+	return types.ModelProfile{
+		Name:            "Generic " + mode + " Pole Display",
+		Manufacturer:    "Generic",
+		Model:           mode,
+		Columns:         20,
+		Rows:            2,
+		DefaultBaudRate: 9600,
+		DefaultDataBits: 8,
+		DefaultParity:   serial.NoParity,
+		DefaultStopBits: serial.OneStopBit,
+		CommandProtocol: protocol,
+		CodePages: types.CodePageTable{
+			types.CodePagePC437: 0,
+		},
+	}
+}
@@ -0,0 +1,39 @@
+package generic
+
+import (
+	"github.com/corrreia/govfd/types"
+
+	"go.bug.st/serial"
+)
+
+// MultiEmulationProfile contains the specification for a generic 20x2 pole
+// display whose emulation is picked by DIP switch: ESC/POS by default, or
+// CD5220, DSP800 or Aedex. Select the one the switches are set to with
+// Options.Protocol; there is no select command, so SelectEmulation is
+// unsupported. Only the power-on PC437 table is declared.
+var MultiEmulationProfile = types.ModelProfile{
+	Name:                 "Generic Multi-Emulation Pole Display",
+	Manufacturer:         "Generic",
+	Model:                "Multi-Emulation",
+	Columns:              20,
+	Rows:                 2,
+	DefaultBaudRate:      9600,
+	DefaultDataBits:      8,
+	DefaultParity:        serial.NoParity,
+	DefaultStopBits:      serial.OneStopBit,
+	CommandProtocol:      types.ProtocolESCPOS,
+	SupportsBrightness:   true,
+	BrightnessLevels:     4,
+	SupportsCursorBlink:  false,
+	SupportsCharsetTable: true,
+	SupportsSelfTest:     false,
+	CodePages: types.CodePageTable{
+		types.CodePagePC437: 0,
+	},
+	Emulations: []types.Emulation{
+		{Protocol: types.ProtocolESCPOS},
+		{Protocol: types.ProtocolCD5220},
+		{Protocol: types.ProtocolDSP800},
+		{Protocol: types.ProtocolAedex},
+	},
+}
@@ -0,0 +1,42 @@
+package logiccontrols
+
+import (
+	"github.com/corrreia/govfd/types"
+
+	"go.bug.st/serial"
+)
+
+// PD3000Profile contains the specification for the Logic Controls PD3000
+// pole display in its native command mode. Its character set is fixed.
+var PD3000Profile = types.ModelProfile{
+	Name:                 "Logic Controls PD3000",
+	Manufacturer:         "Logic Controls",
+	Model:                "PD3000",
+	Columns:              20,
+	Rows:                 2,
+	DefaultBaudRate:      9600,
+	DefaultDataBits:      8,
+	DefaultParity:        serial.NoParity,
+	DefaultStopBits:      serial.OneStopBit,
+	CommandProtocol:      types.ProtocolLogicControls,
+	SupportsBrightness:   true,
+	BrightnessLevels:     4,
+	SupportsCursorBlink:  false,
+	SupportsCharsetTable: false,
+	SupportsSelfTest:     false,
+	CodePages: types.CodePageTable{
+		types.CodePagePC437: 0,
+	},
+}
+
+// LD9000Profile contains the specification for the Logic Controls LD9000
+// line display, which shares the PD3000 command set.
+var LD9000Profile = ld9000()
+
+// ld9000 derives the LD9000 profile from PD3000Profile.
+func ld9000() types.ModelProfile {
+	profile := PD3000Profile
+	profile.Name = "Logic Controls LD9000"
+	profile.Model = "LD9000"
+	return profile
+}
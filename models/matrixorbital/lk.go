@@ -0,0 +1,49 @@
+package matrixorbital
+
+import (
+	"github.com/corrreia/govfd/types"
+
+	"go.bug.st/serial"
+)
+
+// LK162Profile contains the specification for the Matrix Orbital LK162
+// (16x2) module. The character ROM is the standard HD44780 one: ASCII with
+// '¥' and '→' in place of '\' and '~', and half-width katakana.
+var LK162Profile = types.ModelProfile{
+	Name:                 "Matrix Orbital LK162",
+	Manufacturer:         "Matrix Orbital",
+	Model:                "LK162-12",
+	Columns:              16,
+	Rows:                 2,
+	DefaultBaudRate:      19200,
+	DefaultDataBits:      8,
+	DefaultParity:        serial.NoParity,
+	DefaultStopBits:      serial.OneStopBit,
+	CommandProtocol:      types.ProtocolMatrixOrbital,
+	SupportsBrightness:   true,
+	BrightnessLevels:     4,
+	SupportsCursorBlink:  false,
+	SupportsCharsetTable: false,
+	SupportsSelfTest:     false,
+	CodePages: types.CodePageTable{
+		types.CodePageHD44780: 0,
+	},
+}
+
+// LK204Profile contains the specification for the Matrix Orbital LK204
+// (20x4) module.
+var LK204Profile = lk("Matrix Orbital LK204", "LK204-25", 20, 4)
+
+// LK402Profile contains the specification for the Matrix Orbital LK402
+// (40x2) module.
+var LK402Profile = lk("Matrix Orbital LK402", "LK402-12", 40, 2)
+
+// lk derives a profile of another geometry from LK162Profile.
+func lk(name, model string, columns, rows int) types.ModelProfile {
+	profile := LK162Profile
+	profile.Name = name
+	profile.Model = model
+	profile.Columns = columns
+	profile.Rows = rows
+	return profile
+}
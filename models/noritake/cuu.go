@@ -0,0 +1,43 @@
+package noritake
+
+import (
+	"github.com/corrreia/govfd/types"
+
+	"go.bug.st/serial"
+)
+
+// CU20025Profile contains the specification for the Noritake Itron CU20025
+// (20x2) CU-U series module.
+var CU20025Profile = types.ModelProfile{
+	Name:                 "Noritake Itron CU20025",
+	Manufacturer:         "Noritake Itron",
+	Model:                "CU20025-UW",
+	Columns:              20,
+	Rows:                 2,
+	DefaultBaudRate:      19200,
+	DefaultDataBits:      8,
+	DefaultParity:        serial.NoParity,
+	DefaultStopBits:      serial.OneStopBit,
+	CommandProtocol:      types.ProtocolNoritakeCU,
+	SupportsBrightness:   true,
+	BrightnessLevels:     4,
+	SupportsCursorBlink:  false,
+	SupportsCharsetTable: true,
+	SupportsSelfTest:     false,
+	CodePages: types.CodePageTable{
+		types.CodePagePC437: 0,
+	},
+}
+
+// CU20045Profile contains the specification for the Noritake Itron CU20045
+// (20x4) CU-U series module.
+var CU20045Profile = cu20045()
+
+// cu20045 derives the 20x4 profile from CU20025Profile.
+func cu20045() types.ModelProfile {
+	profile := CU20025Profile
+	profile.Name = "Noritake Itron CU20045"
+	profile.Model = "CU20045-UW"
+	profile.Rows = 4
+	return profile
+}
@@ -0,0 +1,45 @@
+package wincor
+
+import (
+	"github.com/corrreia/govfd/commands/ba63"
+	"github.com/corrreia/govfd/types"
+
+	"go.bug.st/serial"
+)
+
+// BA63Profile contains the specification for the Wincor Nixdorf BA63
+// (serial variant).
+var BA63Profile = types.ModelProfile{
+	Name:                 "Wincor Nixdorf BA63",
+	Manufacturer:         "Wincor Nixdorf",
+	Model:                "BA63",
+	Columns:              20,
+	Rows:                 2,
+	DefaultBaudRate:      9600,
+	DefaultDataBits:      8,
+	DefaultParity:        serial.OddParity,
+	DefaultStopBits:      serial.OneStopBit,
+	CommandProtocol:      types.ProtocolBA63,
+	SupportsBrightness:   false,
+	SupportsCursorBlink:  false,
+	SupportsCharsetTable: true,
+	SupportsSelfTest:     false,
+	CodePages: types.CodePageTable{
+		types.CodePagePC437: ba63.CountryPC437,
+		types.CodePagePC850: ba63.CountryPC850,
+		types.CodePagePC858: ba63.CountryPC858,
+	},
+}
+
+// BA66Profile contains the specification for the Wincor Nixdorf BA66, the
+// four-line member of the family.
+var BA66Profile = ba66()
+
+// ba66 derives the BA66 profile from BA63Profile.
+func ba66() types.ModelProfile {
+	profile := BA63Profile
+	profile.Name = "Wincor Nixdorf BA66"
+	profile.Model = "BA66"
+	profile.Rows = 4
+	return profile
+}
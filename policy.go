@@ -0,0 +1,160 @@
+package govfd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/corrreia/govfd/types"
+)
+
+// EncodingPolicy controls what WriteText does with characters the display
+// cannot show exactly.
+type EncodingPolicy int
+
+const (
+	// EncodingReplace transliterates what it can and replaces the rest with
+	// '?'. This is the default.
+	EncodingReplace EncodingPolicy = iota
+
+	// EncodingTransliterate allows transliteration (e.g. full-width to
+	// half-width Katakana) but fails instead of replacing with '?'.
+	EncodingTransliterate
+
+	// EncodingStrict fails unless every character is shown as itself.
+	EncodingStrict
+)
+
+// String returns the policy name.
+func (p EncodingPolicy) String() string {
+	switch p {
+	case EncodingReplace:
+		return "replace"
+	case EncodingTransliterate:
+		return "transliterate"
+	case EncodingStrict:
+		return "strict"
+	}
+	return fmt.Sprintf("EncodingPolicy(%d)", int(p))
+}
+
+// allows reports whether the policy accepts a substitution.
+func (p EncodingPolicy) allows(s types.Substitution) bool {
+	switch p {
+	case EncodingStrict:
+		return false
+	case EncodingTransliterate:
+		return !s.Replaced
+	}
+	return true
+}
+
+// EncodingError is returned by WriteText when the encoding policy rejects
+// some characters of the text. Nothing is sent to the display in that case.
+type EncodingError struct {
+	Policy EncodingPolicy
+	// Runes lists each offending rune with its byte offset in the text and
+	// what it would have been shown as.
+	Runes []types.Substitution
+}
+
+// Error implements the error interface.
+func (e *EncodingError) Error() string {
+	parts := make([]string, len(e.Runes))
+	for i, s := range e.Runes {
+		parts[i] = fmt.Sprintf("%q at offset %d", s.Rune, s.Offset)
+	}
+	return fmt.Sprintf("%s encoding: cannot show %s", e.Policy, strings.Join(parts, ", "))
+}
+
+// SetEncodingPolicy sets how WriteText handles characters the display
+// cannot show exactly.
+func (d *Display) SetEncodingPolicy(policy EncodingPolicy) error {
+	if policy < EncodingReplace || policy > EncodingStrict {
+		return fmt.Errorf("unknown encoding policy %d", int(policy))
+	}
+	d.encodingPolicy = policy
+	return nil
+}
+
+// GetEncodingPolicy returns the current encoding policy.
+func (d *Display) GetEncodingPolicy() EncodingPolicy {
+	return d.encodingPolicy
+}
+
+// SetInvalidUTF8Policy sets how WriteText handles text that is not valid
+// UTF-8: replace each run of invalid bytes with one '?' (the default), reject
+// the text, or read invalid bytes as Latin-1 or Windows-1252 characters.
+func (d *Display) SetInvalidUTF8Policy(policy types.InvalidUTF8Policy) error {
+	encoder, ok := d.encoder.(InvalidUTF8Encoder)
+	if !ok {
+		return errors.New("character encoder does not support invalid UTF-8 policies")
+	}
+	return encoder.SetInvalidUTF8Policy(policy)
+}
+
+// SetCharsetPolicy sets how code tables are selected: pin one table, prefer
+// tables for a locale, or switch to the best table for every text.
+func (d *Display) SetCharsetPolicy(policy types.CharsetPolicy) error {
+	encoder, ok := d.encoder.(CharsetPolicyEncoder)
+	if !ok {
+		return errors.New("character encoder does not support charset policies")
+	}
+	return encoder.SetCharsetPolicy(policy)
+}
+
+// GetCharsetPolicy returns the code table selection policy.
+func (d *Display) GetCharsetPolicy() types.CharsetPolicy {
+	encoder, ok := d.encoder.(CharsetPolicyEncoder)
+	if !ok {
+		return types.CharsetPolicy{}
+	}
+	return encoder.CharsetPolicy()
+}
+
+// PlanCharset prepares the display for a whole screen of text: it selects one
+// code table covering all of lines up front, so writing them with WriteText
+// afterwards switches tables at most once instead of once per line.
+func (d *Display) PlanCharset(lines ...string) error {
+	if d.protocol == nil {
+		return errors.New("no command protocol set")
+	}
+	encoder, ok := d.encoder.(CharsetPolicyEncoder)
+	if !ok {
+		return nil
+	}
+	return encoder.Plan(lines, d.switcher())
+}
+
+// SetApproximation sets the stand-in WriteText shows for r when no code table
+// or user-defined glyph has it, e.g. "(c)" for '©'. It overrides the built-in
+// approximations.
+func (d *Display) SetApproximation(r rune, replacement string) error {
+	encoder, ok := d.encoder.(ApproximationEncoder)
+	if !ok {
+		return errors.New("character encoder does not support approximations")
+	}
+	return encoder.SetApproximation(r, replacement)
+}
+
+// checkEncodingPolicy measures text and returns an *EncodingError if the
+// policy rejects any of its substitutions.
+func (d *Display) checkEncodingPolicy(text string) error {
+	if d.encodingPolicy == EncodingReplace {
+		return nil
+	}
+	m, err := d.Measure(text)
+	if err != nil {
+		return err
+	}
+	var rejected []types.Substitution
+	for _, s := range m.Substitutions {
+		if !d.encodingPolicy.allows(s) {
+			rejected = append(rejected, s)
+		}
+	}
+	if len(rejected) > 0 {
+		return &EncodingError{Policy: d.encodingPolicy, Runes: rejected}
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package govfd
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/corrreia/govfd/commands/escpos"
+	"github.com/corrreia/govfd/types"
+)
+
+func newPolicyTestDisplay(policy EncodingPolicy) (*Display, *mockPort) {
+	d, port := newTestDisplay(20, 2)
+	d.encoder = escpos.NewCharsetEncoder()
+	d.SetEncodingPolicy(policy)
+	return d, port
+}
+
+func TestStrictPolicyRejectsTransliteration(t *testing.T) {
+	d, port := newPolicyTestDisplay(EncodingStrict)
+
+	err := d.WriteText("ガム")
+
+	var encErr *EncodingError
+	if !errors.As(err, &encErr) {
+		t.Fatalf("WriteText error = %v, want *EncodingError", err)
+	}
+	want := []types.Substitution{
+		{Offset: 0, Rune: 'ガ', Replacement: "ｶﾞ"},
+		{Offset: 3, Rune: 'ム', Replacement: "ﾑ"},
+	}
+	if !reflect.DeepEqual(encErr.Runes, want) {
+		t.Errorf("rejected runes = %+v, want %+v", encErr.Runes, want)
+	}
+	// Not even the Katakana page switch may be sent
+	if len(port.written) != 0 {
+		t.Errorf("wrote % X in strict mode, want nothing", port.written)
+	}
+	col, row := d.GetCursor()
+	if col != 0 || row != 0 {
+		t.Errorf("cursor moved to (%d,%d), want unchanged", col, row)
+	}
+}
+
+func TestStrictPolicyAllowsExactText(t *testing.T) {
+	d, port := newPolicyTestDisplay(EncodingStrict)
+
+	if err := d.WriteText("ação"); err != nil {
+		t.Fatalf("WriteText error: %v", err)
+	}
+	if len(port.written) == 0 {
+		t.Error("nothing written for exactly representable text")
+	}
+}
+
+func TestTransliteratePolicy(t *testing.T) {
+	d, port := newPolicyTestDisplay(EncodingTransliterate)
+
+	if err := d.WriteText("ガム"); err != nil {
+		t.Fatalf("WriteText(ガム) error: %v", err)
+	}
+
+	port.written = nil
+	err := d.WriteText("a日b")
+	var encErr *EncodingError
+	if !errors.As(err, &encErr) {
+		t.Fatalf("WriteText error = %v, want *EncodingError", err)
+	}
+	want := []types.Substitution{{Offset: 1, Rune: '日', Replacement: "?", Replaced: true}}
+	if !reflect.DeepEqual(encErr.Runes, want) {
+		t.Errorf("rejected runes = %+v, want %+v", encErr.Runes, want)
+	}
+	if len(port.written) != 0 {
+		t.Errorf("wrote % X, want nothing", port.written)
+	}
+}
+
+func TestReplacePolicyIsDefault(t *testing.T) {
+	d, port := newTestDisplay(20, 2)
+	d.encoder = escpos.NewCharsetEncoder()
+
+	if d.GetEncodingPolicy() != EncodingReplace {
+		t.Errorf("default policy = %v, want replace", d.GetEncodingPolicy())
+	}
+	if err := d.WriteText("a日b"); err != nil {
+		t.Fatalf("WriteText error: %v", err)
+	}
+	if string(port.written) != "a?b" {
+		t.Errorf("wrote %q, want %q", port.written, "a?b")
+	}
+}
+
+func TestSetEncodingPolicyRejectsUnknown(t *testing.T) {
+	d, _ := newTestDisplay(20, 2)
+	if err := d.SetEncodingPolicy(EncodingPolicy(42)); err == nil {
+		t.Fatal("expected error for unknown policy, got nil")
+	}
+}
+
+func TestWriteTextInvalidUTF8KeepsCursorAccurate(t *testing.T) {
+	d, port := newPolicyTestDisplay(EncodingReplace)
+	d.SetCursor(1, 1)
+	port.written = nil
+
+	if err := d.WriteText("Caf\xe9 \xc3"); err != nil {
+		t.Fatalf("WriteText error: %v", err)
+	}
+	if string(port.written) != "Caf? ?" {
+		t.Errorf("wrote %q, want %q", port.written, "Caf? ?")
+	}
+	col, row := d.GetCursor()
+	if col != 7 || row != 1 {
+		t.Errorf("cursor = (%d,%d), want (7,1)", col, row)
+	}
+
+	// Legacy CP1252 data shows as intended
+	d.SetInvalidUTF8Policy(types.InvalidUTF8CP1252)
+	port.written = nil
+	if err := d.WriteText("\x80 5"); err != nil {
+		t.Fatalf("WriteText error: %v", err)
+	}
+	if want := append(escpos.BuildSetCharsetSeq(19), 0xD5, ' ', '5'); string(port.written) != string(want) {
+		t.Errorf("wrote % X, want % X", port.written, want)
+	}
+}
+
+func TestStrictPolicyRejectsInvalidUTF8(t *testing.T) {
+	d, port := newPolicyTestDisplay(EncodingStrict)
+
+	err := d.WriteText("ok\xff")
+	var encErr *EncodingError
+	if !errors.As(err, &encErr) {
+		t.Fatalf("WriteText error = %v, want *EncodingError", err)
+	}
+	if len(encErr.Runes) != 1 || encErr.Runes[0].Offset != 2 {
+		t.Errorf("rejected runes = %+v, want the byte at offset 2", encErr.Runes)
+	}
+	if len(port.written) != 0 {
+		t.Errorf("wrote % X, want nothing", port.written)
+	}
+}
@@ -1,50 +1,246 @@
 package govfd
 
 import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/corrreia/govfd/commands/aedex"
+	"github.com/corrreia/govfd/commands/ba63"
+	"github.com/corrreia/govfd/commands/cd5220"
+	"github.com/corrreia/govfd/commands/dsp800"
 	"github.com/corrreia/govfd/commands/escpos"
+	"github.com/corrreia/govfd/commands/logiccontrols"
+	"github.com/corrreia/govfd/commands/matrixorbital"
+	"github.com/corrreia/govfd/commands/noritake"
+	"github.com/corrreia/govfd/types"
 )
 
+// ErrUnsupported is returned, possibly wrapped, when a display's protocol
+// lacks the requested operation. Test for it with errors.Is.
+var ErrUnsupported = types.ErrUnsupported
+
 // Protocol interface defines high-level operations that any VFD command protocol must implement.
 // This abstracts away the specific implementation (bytes, API calls, etc.) used by different protocols.
-type Protocol interface {
-	// Protocol identification
-	GetName() string
-	GetDescription() string
+//
+// Each method returns the command bytes, or an error when its arguments are
+// out of range for the protocol. A protocol without an equivalent command
+// returns an error wrapping ErrUnsupported. Features only some protocols have
+// are expressed as optional interfaces (ReverseProtocol, GlyphProtocol, ...)
+// that Display detects with type assertions.
+//
+// The interface is declared in the types package so protocol packages can
+// name it; see types.Protocol for the methods.
+type Protocol = types.Protocol
+
+// ModelProtocol is implemented by protocols that depend on the model's
+// geometry or keep per-display state. Displays opened for a model use the
+// protocol ForModel returns instead of the registered one.
+type ModelProtocol interface {
+	Protocol
+	ForModel(profile *types.ModelProfile) (Protocol, error)
+}
+
+// DoubleByteProtocol is implemented by protocols with a double-byte (Kanji)
+// character mode.
+type DoubleByteProtocol interface {
+	Protocol
+	SetDoubleByteMode(enabled bool) ([]byte, error) // Enter/leave double-byte mode
+}
+
+// InternationalCharsetProtocol is implemented by protocols that select
+// national variants of ASCII.
+type InternationalCharsetProtocol interface {
+	Protocol
+	SetInternationalCharset(charset types.InternationalCharset) ([]byte, error)
+}
+
+// GlyphProtocol is implemented by protocols that download user-defined
+// characters.
+type GlyphProtocol interface {
+	Protocol
+	DefineGlyph(code byte, glyph types.Glyph) ([]byte, error) // Download glyph at code and activate it
+	CancelGlyph(code byte) ([]byte, error)                    // Restore built-in character at code
+}
+
+// ReverseProtocol is implemented by protocols that show text in reverse
+// (dark on lit) characters.
+type ReverseProtocol interface {
+	Protocol
+	SetReverse(enabled bool) ([]byte, error)
+}
+
+// DisplayModeProtocol is implemented by protocols that choose what happens
+// when text reaches the end of the screen.
+type DisplayModeProtocol interface {
+	Protocol
+	SetDisplayMode(mode types.DisplayMode) ([]byte, error)
+}
+
+// ClockProtocol is implemented by protocols whose displays have a built-in
+// clock.
+type ClockProtocol interface {
+	Protocol
+	SetClock(hour, minute int) ([]byte, error) // Set the clock and show it
+	ShowClock() ([]byte, error)                // Show the clock
+}
+
+// StatusProtocol is implemented by protocols whose displays report their
+// status over the serial line.
+type StatusProtocol interface {
+	Protocol
+	// RequestStatus returns the command asking the display for its status.
+	RequestStatus() ([]byte, error)
+	// ParseStatus decodes the reply received so far. It returns an error
+	// wrapping io.ErrUnexpectedEOF while the reply is incomplete.
+	ParseStatus(reply []byte) (*types.Status, error)
+}
+
+// LineUploadProtocol is implemented by protocols that replace a whole line
+// in one command, independently of the cursor.
+type LineUploadProtocol interface {
+	Protocol
+	UploadLine(row int, text []byte) ([]byte, error) // Replace row with encoded text
+}
+
+// MarqueeProtocol is implemented by protocols whose displays scroll a
+// message by themselves.
+type MarqueeProtocol interface {
+	Protocol
+	Marquee(text []byte) ([]byte, error) // Scroll encoded text continuously
+}
+
+// TextProtocol is implemented by protocols that cannot take text as plain
+// bytes at the cursor, typically because they only replace whole lines.
+// WriteText then sends the commands it returns instead of the text.
+type TextProtocol interface {
+	Protocol
+	WriteText(text []byte) ([]byte, error) // Show encoded text at the cursor
+}
 
-	// Display control operations
-	Clear() []byte                     // Initialize/clear display
-	FormFeed() []byte                  // Clear screen content
-	MoveCursor(column, row int) []byte // Move cursor to position (1-based)
+// ShadowProtocol is implemented by protocols that emulate the cursor on a
+// shadow copy of the screen. The commands they build change the shadow only
+// once Commit confirms they were written, so a failed write leaves it in
+// step with the display.
+type ShadowProtocol interface {
+	Protocol
+	Commit()                   // Apply the last command built to the shadow screen
+	Cursor() (column, row int) // Emulated 1-based cursor, (0, 0) if unknown
+}
+
+// ESC/POS has every optional capability except status, line upload and marquee.
+var (
+	_ DoubleByteProtocol           = (*escpos.ESCPOSProtocol)(nil)
+	_ InternationalCharsetProtocol = (*escpos.ESCPOSProtocol)(nil)
+	_ GlyphProtocol                = (*escpos.ESCPOSProtocol)(nil)
+	_ ReverseProtocol              = (*escpos.ESCPOSProtocol)(nil)
+	_ DisplayModeProtocol          = (*escpos.ESCPOSProtocol)(nil)
+	_ ClockProtocol                = (*escpos.ESCPOSProtocol)(nil)
+)
 
-	// Display settings
-	SetBrightness(level int) []byte // Set brightness (1-4 typically)
-	SetBlink(intervalMs int) []byte // Set cursor blink (0=off)
-	SetCharset(page int) []byte     // Set character encoding table
+// Logic Controls addresses the cursor by the screen width.
+var _ ModelProtocol = (*logiccontrols.LogicControlsProtocol)(nil)
 
-	// Utility operations
-	SelfTest() []byte // Execute self-test
+// Noritake CU-U modules download custom font characters.
+var _ GlyphProtocol = (*noritake.NoritakeProtocol)(nil)
+
+// Matrix Orbital text must not contain the command prefix.
+var _ TextProtocol = (*matrixorbital.MatrixOrbitalProtocol)(nil)
+
+// Aedex and DSP800 emulate the cursor with a shadow screen per display.
+var (
+	_ ModelProtocol      = (*aedex.AedexProtocol)(nil)
+	_ TextProtocol       = (*aedex.AedexProtocol)(nil)
+	_ LineUploadProtocol = (*aedex.AedexProtocol)(nil)
+	_ ShadowProtocol     = (*aedex.AedexProtocol)(nil)
+	_ ModelProtocol      = (*dsp800.DSP800Protocol)(nil)
+	_ TextProtocol       = (*dsp800.DSP800Protocol)(nil)
+	_ LineUploadProtocol = (*dsp800.DSP800Protocol)(nil)
+	_ ShadowProtocol     = (*dsp800.DSP800Protocol)(nil)
+)
+
+// CD5220 uploads lines and scrolls marquees in hardware.
+var (
+	_ DisplayModeProtocol = (*cd5220.CD5220Protocol)(nil)
+	_ LineUploadProtocol  = (*cd5220.CD5220Protocol)(nil)
+	_ MarqueeProtocol     = (*cd5220.CD5220Protocol)(nil)
+)
+
+// unsupported returns the error for an operation the protocol lacks.
+func unsupported(protocol Protocol, what string) error {
+	return fmt.Errorf("%s: %w by protocol %s", what, ErrUnsupported, protocol.GetName())
 }
 
-// commandRegistry contains implementations for all supported command protocols.
-var commandRegistry = []Protocol{
-	&escpos.ESCPOSProtocol{},
+var (
+	// commandRegistry contains implementations for all supported command
+	// protocols, by name.
+	commandRegistry = map[string]Protocol{
+		types.ProtocolESCPOS:        &escpos.ESCPOSProtocol{},
+		types.ProtocolCD5220:        &cd5220.CD5220Protocol{},
+		types.ProtocolBA63:          &ba63.BA63Protocol{},
+		types.ProtocolLogicControls: &logiccontrols.LogicControlsProtocol{},
+		types.ProtocolAedex:         &aedex.AedexProtocol{},
+		types.ProtocolDSP800:        &dsp800.DSP800Protocol{},
+		types.ProtocolNoritakeCU:    &noritake.NoritakeProtocol{},
+		types.ProtocolMatrixOrbital: &matrixorbital.MatrixOrbitalProtocol{},
+	}
+	commandRegistryMu sync.RWMutex
+)
+
+// RegisterProtocol adds a command protocol, making it available to models
+// whose CommandProtocol is its name. It is safe to call concurrently, e.g.
+// from the init functions of several packages. Registering a second protocol
+// under a name already taken is an error.
+func RegisterProtocol(protocol Protocol) error {
+	if protocol == nil {
+		return errors.New("protocol is nil")
+	}
+	name := protocol.GetName()
+	if name == "" {
+		return errors.New("protocol name is required")
+	}
+	commandRegistryMu.Lock()
+	defer commandRegistryMu.Unlock()
+	if _, exists := commandRegistry[name]; exists {
+		return errors.New("protocol already registered: " + name)
+	}
+	commandRegistry[name] = protocol
+	return nil
 }
 
 // GetProtocol returns the command protocol implementation for the specified protocol name.
 func GetProtocol(protocolName string) (Protocol, bool) {
-	for _, protocol := range commandRegistry {
-		if protocol.GetName() == protocolName {
-			return protocol, true
+	commandRegistryMu.RLock()
+	defer commandRegistryMu.RUnlock()
+	protocol, exists := commandRegistry[protocolName]
+	return protocol, exists
+}
+
+// protocolForModel returns the protocol a display of the given model uses.
+func protocolForModel(profile *types.ModelProfile) (Protocol, error) {
+	protocol, exists := GetProtocol(profile.CommandProtocol)
+	if !exists {
+		return nil, errors.New("unsupported command protocol: " + profile.CommandProtocol)
+	}
+	if specific, ok := protocol.(ModelProtocol); ok {
+		modelProtocol, err := specific.ForModel(profile)
+		if err != nil {
+			return nil, errors.New("model " + profile.Name + ": " + err.Error())
 		}
+		return modelProtocol, nil
 	}
-	return nil, false
+	return protocol, nil
 }
 
-// GetSupportedProtocols returns a list of all supported command protocol names.
+// GetSupportedProtocols returns the names of all supported command protocols, sorted.
 func GetSupportedProtocols() []string {
+	commandRegistryMu.RLock()
+	defer commandRegistryMu.RUnlock()
 	protocolList := make([]string, 0, len(commandRegistry))
-	for _, protocol := range commandRegistry {
-		protocolList = append(protocolList, protocol.GetName())
+	for name := range commandRegistry {
+		protocolList = append(protocolList, name)
 	}
+	slices.Sort(protocolList)
 	return protocolList
 }
@@ -0,0 +1,524 @@
+package govfd
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/corrreia/govfd/types"
+)
+
+// newModelTestDisplay creates a Display for model with a mock serial port,
+// set up as OpenModel would.
+func newModelTestDisplay(t *testing.T, model types.Model) (*Display, *mockPort) {
+	t.Helper()
+	profile := mustProfile(t, model)
+	protocol, err := protocolForModel(profile)
+	if err != nil {
+		t.Fatalf("protocolForModel error: %v", err)
+	}
+	encoder, err := newModelEncoder(protocol, profile)
+	if err != nil {
+		t.Fatalf("newModelEncoder error: %v", err)
+	}
+	d, port := newTestDisplay(profile.Columns, profile.Rows)
+	d.protocol = protocol
+	d.encoder = encoder
+	return d, port
+}
+
+func TestCD5220Commands(t *testing.T) {
+	d, port := newModelTestDisplay(t, types.ModelGenericCD5220)
+
+	if err := d.SetCursor(20, 2); err != nil {
+		t.Fatalf("SetCursor error: %v", err)
+	}
+	if err := d.SetBrightness(3); err != nil {
+		t.Fatalf("SetBrightness error: %v", err)
+	}
+	if err := d.WriteText("Café"); err != nil {
+		t.Fatalf("WriteText error: %v", err)
+	}
+	want := "\x1bl\x14\x02" + "\x1b*\x03" + "Caf\x82"
+	if string(port.written) != want {
+		t.Errorf("wrote % X, want % X", port.written, want)
+	}
+	if err := d.SetBlink(500); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("SetBlink error = %v, want ErrUnsupported", err)
+	}
+}
+
+func TestCD5220WriteLine(t *testing.T) {
+	d, port := newModelTestDisplay(t, types.ModelGenericCD5220)
+	d.SetCursor(1, 1)
+	port.written = nil
+
+	if err := d.WriteLine(2, "Total: 5€"); err != nil {
+		t.Fatalf("WriteLine error: %v", err)
+	}
+	// € is not in PC437, the only table of the generic profile
+	want := "\x1bQBTotal: 5EUR         \r"
+	if string(port.written) != want {
+		t.Errorf("wrote %q, want %q", port.written, want)
+	}
+	if col, row := d.GetCursor(); col != 0 || row != 0 {
+		t.Errorf("cursor = (%d,%d) after line upload, want unknown (0,0)", col, row)
+	}
+
+	if err := d.WriteLine(1, "This line is far too long"); err == nil {
+		t.Error("WriteLine accepted text wider than the display")
+	}
+	if err := d.WriteLine(3, "x"); err == nil {
+		t.Error("WriteLine accepted row 3 on a 2-row display")
+	}
+}
+
+func TestCD5220Marquee(t *testing.T) {
+	d, port := newModelTestDisplay(t, types.ModelGenericCD5220)
+
+	if err := d.Marquee("Welcome!"); err != nil {
+		t.Fatalf("Marquee error: %v", err)
+	}
+	if want := "\x1bQDWelcome!\r"; string(port.written) != want {
+		t.Errorf("wrote %q, want %q", port.written, want)
+	}
+	if err := d.Marquee("This marquee message is longer than forty bytes"); err == nil {
+		t.Error("Marquee accepted more than 40 bytes")
+	}
+}
+
+func TestMarqueeUnsupportedOnESCPOS(t *testing.T) {
+	d, port := newTestDisplay(20, 2)
+	if err := d.Marquee("Hi"); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Marquee error = %v, want ErrUnsupported", err)
+	}
+	if err := d.WriteLine(1, "Hi"); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("WriteLine error = %v, want ErrUnsupported", err)
+	}
+	if len(port.written) != 0 {
+		t.Errorf("wrote % X", port.written)
+	}
+}
+
+func TestBA63Commands(t *testing.T) {
+	d, port := newModelTestDisplay(t, types.ModelWincorBA63)
+
+	if err := d.SetCursor(12, 2); err != nil {
+		t.Fatalf("SetCursor error: %v", err)
+	}
+	if err := d.WriteText("ação"); err != nil {
+		t.Fatalf("WriteText error: %v", err)
+	}
+	want := "\x1b[2;12H" + "\x1bR\x31" + "a\x87\xc6o"
+	if string(port.written) != want {
+		t.Errorf("wrote % X, want % X", port.written, want)
+	}
+
+	port.written = nil
+	if err := d.Clear(); err != nil {
+		t.Fatalf("Clear error: %v", err)
+	}
+	if err := d.WriteText("ã"); err != nil {
+		t.Fatalf("WriteText error: %v", err)
+	}
+	want = "\x1b[2J\x1bR\x30" + "\x1bR\x31\xc6"
+	if string(port.written) != want {
+		t.Errorf("after Clear wrote % X, want % X (Clear restores PC437)", port.written, want)
+	}
+
+	if err := d.SetBrightness(2); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("SetBrightness error = %v, want ErrUnsupported", err)
+	}
+}
+
+func TestBA66FourRows(t *testing.T) {
+	d, port := newModelTestDisplay(t, types.ModelWincorBA66)
+	if err := d.SetCursor(20, 4); err != nil {
+		t.Fatalf("SetCursor error: %v", err)
+	}
+	if want := "\x1b[4;20H"; string(port.written) != want {
+		t.Errorf("wrote %q, want %q", port.written, want)
+	}
+	if err := d.SetCursor(1, 5); err == nil {
+		t.Error("SetCursor(1, 5) succeeded on a 4-row display")
+	}
+}
+
+func TestLogicControlsLinearCursor(t *testing.T) {
+	d, port := newModelTestDisplay(t, types.ModelLogicControlsPD3000)
+
+	tests := []struct {
+		column, row int
+		position    byte
+	}{
+		{1, 1, 0},
+		{20, 1, 19},
+		{1, 2, 20},
+		{5, 2, 24},
+		{20, 2, 39},
+	}
+	for _, tt := range tests {
+		port.written = nil
+		if err := d.SetCursor(tt.column, tt.row); err != nil {
+			t.Fatalf("SetCursor(%d, %d) error: %v", tt.column, tt.row, err)
+		}
+		if want := []byte{0x10, tt.position}; string(port.written) != string(want) {
+			t.Errorf("SetCursor(%d, %d) wrote % X, want % X", tt.column, tt.row, port.written, want)
+		}
+	}
+}
+
+func TestLogicControlsBrightnessLevels(t *testing.T) {
+	d, port := newModelTestDisplay(t, types.ModelLogicControlsLD9000)
+	for level, want := range map[int]byte{1: 0x20, 2: 0x40, 3: 0x60, 4: 0xFF} {
+		port.written = nil
+		if err := d.SetBrightness(level); err != nil {
+			t.Fatalf("SetBrightness(%d) error: %v", level, err)
+		}
+		if string(port.written) != string([]byte{0x04, want}) {
+			t.Errorf("SetBrightness(%d) wrote % X, want 04 %02X", level, port.written, want)
+		}
+	}
+	if err := d.SetBrightness(5); err == nil {
+		t.Error("SetBrightness(5) succeeded")
+	}
+}
+
+func TestLogicControlsClearAndText(t *testing.T) {
+	d, port := newModelTestDisplay(t, types.ModelLogicControlsPD3000)
+	if err := d.Clear(); err != nil {
+		t.Fatalf("Clear error: %v", err)
+	}
+	if err := d.WriteText("ação"); err != nil {
+		t.Fatalf("WriteText error: %v", err)
+	}
+	// The fixed PC437 set has ç but not ã, and no code table switch is sent
+	if want := "\x1fa\x87?o"; string(port.written) != want {
+		t.Errorf("wrote % X, want % X", port.written, want)
+	}
+
+	port.written = nil
+	if err := d.FormFeed(); err != nil {
+		t.Fatalf("FormFeed error: %v", err)
+	}
+	if len(port.written) != 2+40+2 {
+		t.Errorf("FormFeed wrote %d bytes, want home, 40 spaces, home", len(port.written))
+	}
+}
+
+func TestLogicControlsSizedByModel(t *testing.T) {
+	wide := *mustProfile(t, types.ModelLogicControlsPD3000)
+	wide.Columns = 40
+	protocol, err := protocolForModel(&wide)
+	if err != nil {
+		t.Fatalf("protocolForModel error: %v", err)
+	}
+	if got, _ := protocol.MoveCursor(1, 2); string(got) != "\x10\x28" {
+		t.Errorf("MoveCursor(1, 2) on 40 columns = % X, want 10 28", got)
+	}
+	if registered, _ := GetProtocol(types.ProtocolLogicControls); registered == protocol {
+		t.Error("the registered protocol was resized instead of a per-model copy")
+	}
+
+	huge := wide
+	huge.Rows = 8
+	if _, err := protocolForModel(&huge); err == nil {
+		t.Error("a 40x8 screen was accepted with one position byte")
+	}
+}
+
+func TestAedexEmulatesCursorWithShadowScreen(t *testing.T) {
+	d, port := newModelTestDisplay(t, types.ModelGenericAedex)
+
+	if err := d.Clear(); err != nil {
+		t.Fatalf("Clear error: %v", err)
+	}
+	if want := "!#4" + strings.Repeat(" ", 40) + "\r"; string(port.written) != want {
+		t.Errorf("Clear wrote %q, want %q", port.written, want)
+	}
+
+	port.written = nil
+	if err := d.SetCursor(5, 2); err != nil {
+		t.Fatalf("SetCursor error: %v", err)
+	}
+	if err := d.WriteText("Olá"); err != nil {
+		t.Fatalf("WriteText error: %v", err)
+	}
+	want := "!#2" + strings.Repeat(" ", 20) + "\r" +
+		"!#2    Ol\xa0" + strings.Repeat(" ", 13) + "\r"
+	if string(port.written) != want {
+		t.Errorf("wrote %q, want %q", port.written, want)
+	}
+
+	// Text running off the last line wraps to the first, resending both
+	port.written = nil
+	d.SetCursor(19, 2)
+	port.written = nil
+	if err := d.WriteText("abc"); err != nil {
+		t.Fatalf("WriteText error: %v", err)
+	}
+	want = "!#2    Ol\xa0" + strings.Repeat(" ", 11) + "ab\r" +
+		"!#1c" + strings.Repeat(" ", 19) + "\r"
+	if string(port.written) != want {
+		t.Errorf("wrapped write sent %q, want %q", port.written, want)
+	}
+	if col, row := d.GetCursor(); col != 2 || row != 1 {
+		t.Errorf("cursor = (%d,%d), want (2,1)", col, row)
+	}
+}
+
+func TestDSP800LineCommands(t *testing.T) {
+	d, port := newModelTestDisplay(t, types.ModelGenericDSP800)
+
+	if err := d.WriteText("Hi"); err != nil {
+		t.Fatalf("WriteText error: %v", err)
+	}
+	if err := d.WriteLine(2, "Total 5"); err != nil {
+		t.Fatalf("WriteLine error: %v", err)
+	}
+	want := "\x1bQAHi" + strings.Repeat(" ", 18) + "\r" +
+		"\x1bQBTotal 5" + strings.Repeat(" ", 13) + "\r"
+	if string(port.written) != want {
+		t.Errorf("wrote %q, want %q", port.written, want)
+	}
+
+	port.written = nil
+	if err := d.FormFeed(); err != nil {
+		t.Fatalf("FormFeed error: %v", err)
+	}
+	d.SetCursor(1, 2)
+	if want := "\x0c\x1bQB" + strings.Repeat(" ", 20) + "\r"; string(port.written) != want {
+		t.Errorf("after FormFeed wrote %q, want %q (shadow screen blanked)", port.written, want)
+	}
+	if err := d.SetBrightness(2); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("SetBrightness error = %v, want ErrUnsupported", err)
+	}
+}
+
+func TestLineDisplaysHaveOwnShadowScreens(t *testing.T) {
+	a, _ := newModelTestDisplay(t, types.ModelGenericDSP800)
+	b, port := newModelTestDisplay(t, types.ModelGenericDSP800)
+	a.WriteText("first")
+	b.WriteText("x")
+	if want := "\x1bQAx" + strings.Repeat(" ", 19) + "\r"; string(port.written) != want {
+		t.Errorf("second display wrote %q, want %q", port.written, want)
+	}
+}
+
+func TestLineDisplaysFollowCursorAfterWriteLine(t *testing.T) {
+	for _, tt := range []struct {
+		model types.Model
+		line  func(row int, text string) string
+	}{
+		{types.ModelGenericAedex, func(row int, text string) string { return fmt.Sprintf("!#%d%-20s\r", row, text) }},
+		{types.ModelGenericDSP800, func(row int, text string) string { return fmt.Sprintf("\x1bQ%c%-20s\r", 'A'+row-1, text) }},
+	} {
+		d, port := newModelTestDisplay(t, tt.model)
+		d.SetCursor(3, 1)
+		d.WriteText("ab")
+		if err := d.WriteLine(2, "Total"); err != nil {
+			t.Fatalf("%s: WriteLine error: %v", tt.model, err)
+		}
+		if col, row := d.GetCursor(); col != 5 || row != 1 {
+			t.Errorf("%s: cursor after WriteLine = (%d,%d), want (5,1)", tt.model, col, row)
+		}
+
+		port.written = nil
+		d.SetCursor(5, 1)
+		d.WriteText("c")
+		d.SetCursor(1, 2)
+		d.WriteText("X")
+		want := tt.line(1, "  abc") + tt.line(2, "Total") + tt.line(2, "Xotal")
+		if string(port.written) != want {
+			t.Errorf("%s: wrote %q, want %q", tt.model, port.written, want)
+		}
+	}
+}
+
+func TestLineDisplayShadowSurvivesFailedWrite(t *testing.T) {
+	d, port := newModelTestDisplay(t, types.ModelGenericDSP800)
+	d.WriteText("ab")
+	port.failOnNext = true
+	if err := d.WriteText("cd"); err == nil {
+		t.Fatal("WriteText succeeded on a failing port")
+	}
+	port.written = nil
+	d.WriteText("ef")
+	if want := "\x1bQAabef" + strings.Repeat(" ", 16) + "\r"; string(port.written) != want {
+		t.Errorf("after a failed write wrote %q, want %q", port.written, want)
+	}
+}
+
+func TestRegisteredLineProtocolsKeepNoState(t *testing.T) {
+	for _, name := range []string{types.ProtocolAedex, types.ProtocolDSP800} {
+		protocol, _ := GetProtocol(name)
+		a, _ := newTestDisplay(20, 2)
+		b, port := newTestDisplay(20, 2)
+		a.protocol, b.protocol = protocol, protocol
+		a.WriteText("first")
+		b.WriteText("x")
+		if !strings.Contains(string(port.written), "x"+strings.Repeat(" ", 19)) {
+			t.Errorf("%s: second display wrote %q, want x on a blank line", name, port.written)
+		}
+	}
+}
+
+func TestNoritakeCommands(t *testing.T) {
+	d, port := newModelTestDisplay(t, types.ModelNoritakeCU20025)
+
+	if err := d.SetCursor(20, 2); err != nil {
+		t.Fatalf("SetCursor error: %v", err)
+	}
+	if err := d.SetBrightness(2); err != nil {
+		t.Fatalf("SetBrightness error: %v", err)
+	}
+	if err := d.WriteText("Olá"); err != nil {
+		t.Fatalf("WriteText error: %v", err)
+	}
+	want := "\x1f$\x14\x02" + "\x1bL\x40" + "Ol\xa0"
+	if string(port.written) != want {
+		t.Errorf("wrote % X, want % X", port.written, want)
+	}
+	if err := d.SetBrightness(5); err == nil {
+		t.Error("SetBrightness(5) succeeded")
+	}
+	if err := d.SetBlink(500); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("SetBlink error = %v, want ErrUnsupported", err)
+	}
+}
+
+func TestNoritakeFourRowCursor(t *testing.T) {
+	d, port := newModelTestDisplay(t, types.ModelNoritakeCU20045)
+
+	if err := d.SetCursor(20, 4); err != nil {
+		t.Fatalf("SetCursor error: %v", err)
+	}
+	if want := "\x1f$\x14\x04"; string(port.written) != want {
+		t.Errorf("wrote %q, want %q", port.written, want)
+	}
+	if err := d.SetCursor(1, 5); err == nil {
+		t.Error("SetCursor(1, 5) succeeded on a 4-row display")
+	}
+}
+
+func TestNoritakeFourRowWrapping(t *testing.T) {
+	d, _ := newModelTestDisplay(t, types.ModelNoritakeCU20045)
+
+	tests := []struct {
+		column, row int
+		text        string
+		wantColumn  int
+		wantRow     int
+	}{
+		{1, 1, strings.Repeat("x", 20), 1, 2},
+		{15, 2, strings.Repeat("x", 10), 5, 3},
+		{1, 3, strings.Repeat("x", 25), 6, 4},
+		{19, 4, "xy", 1, 1},
+		{1, 1, strings.Repeat("x", 70), 11, 4},
+		{1, 2, strings.Repeat("x", 80), 1, 2},
+	}
+	for _, tt := range tests {
+		if err := d.SetCursor(tt.column, tt.row); err != nil {
+			t.Fatalf("SetCursor(%d, %d) error: %v", tt.column, tt.row, err)
+		}
+		if err := d.WriteText(tt.text); err != nil {
+			t.Fatalf("WriteText error: %v", err)
+		}
+		if col, row := d.GetCursor(); col != tt.wantColumn || row != tt.wantRow {
+			t.Errorf("%d chars from (%d,%d): cursor = (%d,%d), want (%d,%d)",
+				len(tt.text), tt.column, tt.row, col, row, tt.wantColumn, tt.wantRow)
+		}
+	}
+}
+
+func TestNoritakeCustomFont(t *testing.T) {
+	d, port := newModelTestDisplay(t, types.ModelNoritakeCU20045)
+	d.SetCursor(1, 1)
+	port.written = nil
+
+	if err := d.DefineGlyph('→', types.Glyph{}); err != nil {
+		t.Fatalf("DefineGlyph error: %v", err)
+	}
+	if err := d.WriteText("→"); err != nil {
+		t.Fatalf("WriteText error: %v", err)
+	}
+	if !strings.HasPrefix(string(port.written), "\x1b&") {
+		t.Errorf("wrote % X, want an ESC & download first", port.written)
+	}
+	if col, row := d.GetCursor(); col != 2 || row != 1 {
+		t.Errorf("cursor = (%d,%d), want (2,1)", col, row)
+	}
+}
+
+func TestMatrixOrbitalGeometries(t *testing.T) {
+	tests := []struct {
+		model       types.Model
+		columns     int
+		rows        int
+		wantCommand string
+	}{
+		{types.ModelMatrixOrbitalLK162, 16, 2, "\xfeG\x10\x02"},
+		{types.ModelMatrixOrbitalLK204, 20, 4, "\xfeG\x14\x04"},
+		{types.ModelMatrixOrbitalLK402, 40, 2, "\xfeG\x28\x02"},
+	}
+	for _, tt := range tests {
+		d, port := newModelTestDisplay(t, tt.model)
+		if err := d.SetCursor(tt.columns, tt.rows); err != nil {
+			t.Fatalf("%s: SetCursor error: %v", tt.model, err)
+		}
+		if string(port.written) != tt.wantCommand {
+			t.Errorf("%s: wrote % X, want % X", tt.model, port.written, tt.wantCommand)
+		}
+		if err := d.SetCursor(tt.columns+1, 1); err == nil {
+			t.Errorf("%s: SetCursor past column %d succeeded", tt.model, tt.columns)
+		}
+	}
+}
+
+func TestMatrixOrbitalText(t *testing.T) {
+	d, port := newModelTestDisplay(t, types.ModelMatrixOrbitalLK402)
+	d.SetCursor(1, 1)
+	port.written = nil
+
+	if err := d.SetBrightness(2); err != nil {
+		t.Fatalf("SetBrightness error: %v", err)
+	}
+	if err := d.WriteText("Tea ｱｲ"); err != nil {
+		t.Fatalf("WriteText error: %v", err)
+	}
+	want := "\xfe\x99\x7f" + "Tea \xb1\xb2"
+	if string(port.written) != want {
+		t.Errorf("wrote % X, want % X", port.written, want)
+	}
+	if col, row := d.GetCursor(); col != 7 || row != 1 {
+		t.Errorf("cursor = (%d,%d), want (7,1)", col, row)
+	}
+}
+
+func TestMatrixOrbitalROMCharacters(t *testing.T) {
+	d, port := newModelTestDisplay(t, types.ModelMatrixOrbitalLK162)
+	d.SetCursor(1, 1)
+	port.written = nil
+
+	if err := d.WriteText("¥5 → ｱ"); err != nil {
+		t.Fatalf("WriteText error: %v", err)
+	}
+	if want := "\x5c5 \x7e \xb1"; string(port.written) != want {
+		t.Errorf("wrote % X, want % X", port.written, want)
+	}
+
+	// The ROM shows '¥' and '→' at the ASCII codes of '\' and '~'.
+	m, err := Measure(types.ModelMatrixOrbitalLK162, `a\b~`)
+	if err != nil {
+		t.Fatalf("Measure error: %v", err)
+	}
+	want := []types.Substitution{
+		{Offset: 1, Rune: '\\', Replacement: "?", Replaced: true},
+		{Offset: 3, Rune: '~', Replacement: "?", Replaced: true},
+	}
+	if !reflect.DeepEqual(m.Substitutions, want) {
+		t.Errorf("Substitutions = %+v, want %+v", m.Substitutions, want)
+	}
+}
@@ -0,0 +1,361 @@
+// Package protocoltest checks that a command protocol implementation
+// behaves as the govfd Display expects.
+//
+// Run it from a test of your protocol:
+//
+//	func TestConformance(t *testing.T) {
+//		protocoltest.Run(t, &MyProtocol{}, protocoltest.Config{
+//			Columns:          20,
+//			Rows:             2,
+//			BrightnessLevels: 4,
+//			CodePages:        []int{0, 2},
+//		})
+//	}
+//
+// Every command the protocol should build is built twice and must come out
+// non-empty and identical; every invalid argument must be rejected. With a
+// Decoder, each command is also decoded back into the call that built it.
+package protocoltest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/corrreia/govfd/types"
+)
+
+// Config describes what the protocol under test accepts.
+type Config struct {
+	// Columns and Rows bound the cursor positions that must be accepted
+	// (default 20x2).
+	Columns int
+	Rows    int
+
+	// BrightnessLevels is the number of brightness levels, 1-based
+	// (0: brightness is unsupported).
+	BrightnessLevels int
+
+	// BlinkIntervals are blink intervals in milliseconds that must be
+	// accepted (nil: blinking is unsupported).
+	BlinkIntervals []int
+
+	// CodePages are code table page numbers that must be accepted
+	// (nil: code table selection is unsupported).
+	CodePages []int
+
+	// Decoder, if set, decodes each command back into its call.
+	Decoder Decoder
+}
+
+// Call is a protocol method call, e.g. {"MoveCursor", []int{1, 2}}.
+type Call struct {
+	Method string
+	Args   []int
+}
+
+// String formats the call as Go source.
+func (c Call) String() string {
+	args := make([]string, len(c.Args))
+	for i, arg := range c.Args {
+		args[i] = fmt.Sprint(arg)
+	}
+	return c.Method + "(" + strings.Join(args, ", ") + ")"
+}
+
+// Decoder turns a command built by the protocol back into the call that
+// built it. It returns an error wrapping types.ErrUnsupported for commands
+// it does not cover, which are then not round-tripped.
+type Decoder interface {
+	Decode(cmd []byte) (Call, error)
+}
+
+// DecoderFunc adapts a function to the Decoder interface.
+type DecoderFunc func(cmd []byte) (Call, error)
+
+// Decode calls f(cmd).
+func (f DecoderFunc) Decode(cmd []byte) (Call, error) {
+	return f(cmd)
+}
+
+// Optional protocol features checked when the protocol has them. These
+// match the optional interfaces of package govfd.
+type (
+	reverseProtocol interface {
+		SetReverse(enabled bool) ([]byte, error)
+	}
+	displayModeProtocol interface {
+		SetDisplayMode(mode types.DisplayMode) ([]byte, error)
+	}
+	clockProtocol interface {
+		SetClock(hour, minute int) ([]byte, error)
+		ShowClock() ([]byte, error)
+	}
+	glyphProtocol interface {
+		DefineGlyph(code byte, glyph types.Glyph) ([]byte, error)
+		CancelGlyph(code byte) ([]byte, error)
+	}
+	lineUploadProtocol interface {
+		UploadLine(row int, text []byte) ([]byte, error)
+	}
+)
+
+// Run checks p against cfg and reports each problem as a test error.
+func Run(t testing.TB, p types.Protocol, cfg Config) {
+	t.Helper()
+	for _, err := range Check(p, cfg) {
+		t.Error(err)
+	}
+}
+
+// Check checks p against cfg and returns the problems found.
+func Check(p types.Protocol, cfg Config) []error {
+	if cfg.Columns == 0 {
+		cfg.Columns = 20
+	}
+	if cfg.Rows == 0 {
+		cfg.Rows = 2
+	}
+	c := &checker{protocol: p, cfg: cfg}
+	c.checkNames()
+	c.checkScreen()
+	c.checkCursor()
+	c.checkBrightness()
+	c.checkBlink()
+	c.checkCharset()
+	c.checkOptional()
+	return c.errs
+}
+
+// checker accumulates the problems found in one protocol.
+type checker struct {
+	protocol types.Protocol
+	cfg      Config
+	errs     []error
+}
+
+func (c *checker) errorf(format string, args ...any) {
+	c.errs = append(c.errs, fmt.Errorf("%s: %s", c.protocol.GetName(), fmt.Sprintf(format, args...)))
+}
+
+// valid checks that build succeeds with the same non-empty command twice,
+// and that the command decodes to call.
+func (c *checker) valid(call Call, build func() ([]byte, error)) {
+	if !c.optional(call, build) {
+		c.errorf("%s: not supported", call)
+	}
+}
+
+// optional checks build like valid, but also accepts an error wrapping
+// types.ErrUnsupported. It reports whether the call is supported.
+func (c *checker) optional(call Call, build func() ([]byte, error)) bool {
+	first, err := build()
+	if errors.Is(err, types.ErrUnsupported) {
+		return false
+	}
+	if err != nil {
+		c.errorf("%s: %v", call, err)
+		return true
+	}
+	if len(first) == 0 {
+		c.errorf("%s: empty command", call)
+		return true
+	}
+	first = bytes.Clone(first)
+	second, err := build()
+	if err != nil || !bytes.Equal(first, second) {
+		c.errorf("%s: not deterministic: % X, then % X (%v)", call, first, second, err)
+		return true
+	}
+	c.roundTrip(call, first)
+	return true
+}
+
+// roundTrip checks that cmd decodes to call.
+func (c *checker) roundTrip(call Call, cmd []byte) {
+	if c.cfg.Decoder == nil {
+		return
+	}
+	decoded, err := c.cfg.Decoder.Decode(cmd)
+	if errors.Is(err, types.ErrUnsupported) {
+		return
+	}
+	if err != nil {
+		c.errorf("%s: decoding % X: %v", call, cmd, err)
+		return
+	}
+	if decoded.Method != call.Method || !slices.Equal(decoded.Args, call.Args) {
+		c.errorf("%s: % X decodes to %s", call, cmd, decoded)
+	}
+}
+
+// invalid checks that build rejects its arguments.
+func (c *checker) invalid(call Call, build func() ([]byte, error)) {
+	if cmd, err := build(); err == nil {
+		c.errorf("%s: accepted invalid input, built % X", call, cmd)
+	}
+}
+
+// unsupported checks that build reports an unsupported feature.
+func (c *checker) unsupported(call Call, build func() ([]byte, error)) {
+	if _, err := build(); !errors.Is(err, types.ErrUnsupported) {
+		c.errorf("%s: error = %v, want one wrapping types.ErrUnsupported", call, err)
+	}
+}
+
+func (c *checker) checkNames() {
+	if c.protocol.GetName() == "" {
+		c.errorf("empty name")
+	}
+	if c.protocol.GetDescription() == "" {
+		c.errorf("empty description")
+	}
+}
+
+func (c *checker) checkScreen() {
+	c.valid(Call{Method: "Clear"}, c.protocol.Clear)
+	c.valid(Call{Method: "FormFeed"}, c.protocol.FormFeed)
+	c.optional(Call{Method: "SelfTest"}, c.protocol.SelfTest)
+}
+
+func (c *checker) checkCursor() {
+	move := func(column, row int) (Call, func() ([]byte, error)) {
+		return Call{"MoveCursor", []int{column, row}}, func() ([]byte, error) {
+			return c.protocol.MoveCursor(column, row)
+		}
+	}
+	for row := 1; row <= c.cfg.Rows; row++ {
+		for column := 1; column <= c.cfg.Columns; column++ {
+			c.valid(move(column, row))
+		}
+	}
+	for _, pos := range [][2]int{{0, 1}, {1, 0}, {-1, 1}, {1, -1}, {256, 1}, {1, 256}} {
+		c.invalid(move(pos[0], pos[1]))
+	}
+}
+
+func (c *checker) checkBrightness() {
+	brightness := func(level int) (Call, func() ([]byte, error)) {
+		return Call{"SetBrightness", []int{level}}, func() ([]byte, error) {
+			return c.protocol.SetBrightness(level)
+		}
+	}
+	if c.cfg.BrightnessLevels == 0 {
+		c.unsupported(brightness(1))
+		return
+	}
+	for level := 1; level <= c.cfg.BrightnessLevels; level++ {
+		c.valid(brightness(level))
+	}
+	c.invalid(brightness(0))
+	c.invalid(brightness(c.cfg.BrightnessLevels + 1))
+}
+
+func (c *checker) checkBlink() {
+	blink := func(ms int) (Call, func() ([]byte, error)) {
+		return Call{"SetBlink", []int{ms}}, func() ([]byte, error) {
+			return c.protocol.SetBlink(ms)
+		}
+	}
+	if c.cfg.BlinkIntervals == nil {
+		c.unsupported(blink(500))
+		return
+	}
+	for _, ms := range c.cfg.BlinkIntervals {
+		c.valid(blink(ms))
+	}
+	c.invalid(blink(-1))
+}
+
+func (c *checker) checkCharset() {
+	charset := func(page int) (Call, func() ([]byte, error)) {
+		return Call{"SetCharset", []int{page}}, func() ([]byte, error) {
+			return c.protocol.SetCharset(page)
+		}
+	}
+	if c.cfg.CodePages == nil {
+		c.unsupported(charset(0))
+		return
+	}
+	for _, page := range c.cfg.CodePages {
+		c.valid(charset(page))
+	}
+	c.invalid(charset(-1))
+	c.invalid(charset(256))
+}
+
+// checkOptional checks the optional features the protocol has. Each must
+// build its commands or report them unsupported, and reject invalid input.
+func (c *checker) checkOptional() {
+	if p, ok := c.protocol.(reverseProtocol); ok {
+		for _, enabled := range []bool{true, false} {
+			c.optional(Call{"SetReverse", []int{boolArg(enabled)}}, func() ([]byte, error) {
+				return p.SetReverse(enabled)
+			})
+		}
+	}
+	if p, ok := c.protocol.(displayModeProtocol); ok {
+		modes := []types.DisplayMode{
+			types.DisplayModeOverwrite, types.DisplayModeVerticalScroll, types.DisplayModeHorizontalScroll,
+		}
+		for i, mode := range modes {
+			c.optional(Call{"SetDisplayMode", []int{i}}, func() ([]byte, error) {
+				return p.SetDisplayMode(mode)
+			})
+		}
+		c.invalid(Call{"SetDisplayMode", []int{-1}}, func() ([]byte, error) {
+			return p.SetDisplayMode("bogus")
+		})
+	}
+	if p, ok := c.protocol.(clockProtocol); ok {
+		clock := func(hour, minute int) (Call, func() ([]byte, error)) {
+			return Call{"SetClock", []int{hour, minute}}, func() ([]byte, error) {
+				return p.SetClock(hour, minute)
+			}
+		}
+		if c.optional(clock(0, 0)) {
+			c.valid(clock(23, 59))
+			c.valid(Call{Method: "ShowClock"}, p.ShowClock)
+			c.invalid(clock(24, 0))
+			c.invalid(clock(0, 60))
+			c.invalid(clock(-1, 0))
+		}
+	}
+	if p, ok := c.protocol.(glyphProtocol); ok {
+		glyph := types.Glyph{0b00000, 0b00001, 0b00010, 0b10100, 0b01000, 0b00000, 0b00000}
+		define := func(code byte) (Call, func() ([]byte, error)) {
+			return Call{"DefineGlyph", []int{int(code)}}, func() ([]byte, error) {
+				return p.DefineGlyph(code, glyph)
+			}
+		}
+		if c.optional(define(0x20)) {
+			c.valid(define(0x7E))
+			c.valid(Call{"CancelGlyph", []int{0x20}}, func() ([]byte, error) {
+				return p.CancelGlyph(0x20)
+			})
+			c.invalid(define(0x1F))
+		}
+	}
+	if p, ok := c.protocol.(lineUploadProtocol); ok {
+		upload := func(row int) (Call, func() ([]byte, error)) {
+			return Call{"UploadLine", []int{row}}, func() ([]byte, error) {
+				return p.UploadLine(row, []byte("OK"))
+			}
+		}
+		if c.optional(upload(1)) {
+			c.invalid(upload(0))
+			c.invalid(upload(c.cfg.Rows + 1))
+		}
+	}
+}
+
+// boolArg represents a boolean argument of a Call.
+func boolArg(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,121 @@
+package protocoltest
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/corrreia/govfd/types"
+)
+
+// sampleProtocol builds ESC/POS-like commands. Its fields break it in the
+// ways the checks look for.
+type sampleProtocol struct {
+	counter       byte // Clear builds a different command each time
+	looseCursor   bool // MoveCursor accepts position 0
+	emptyFormFeed bool // FormFeed builds nothing
+}
+
+func (p *sampleProtocol) GetName() string        { return "SAMPLE" }
+func (p *sampleProtocol) GetDescription() string { return "Sample protocol" }
+
+func (p *sampleProtocol) Clear() ([]byte, error) {
+	if p.counter > 0 {
+		p.counter++
+		return []byte{0x1B, 0x40, p.counter}, nil
+	}
+	return []byte{0x1B, 0x40}, nil
+}
+
+func (p *sampleProtocol) FormFeed() ([]byte, error) {
+	if p.emptyFormFeed {
+		return nil, nil
+	}
+	return []byte{0x0C}, nil
+}
+
+func (p *sampleProtocol) MoveCursor(column, row int) ([]byte, error) {
+	low := 1
+	if p.looseCursor {
+		low = 0
+	}
+	if column < low || column > 255 || row < low || row > 255 {
+		return nil, errors.New("column/row out of range")
+	}
+	return []byte{0x1F, 0x24, byte(column), byte(row)}, nil
+}
+
+func (p *sampleProtocol) SetBrightness(level int) ([]byte, error) {
+	if level < 1 || level > 4 {
+		return nil, errors.New("brightness level must be between 1 and 4")
+	}
+	return []byte{0x1F, 0x58, byte(level)}, nil
+}
+
+func (p *sampleProtocol) SetBlink(intervalMs int) ([]byte, error) {
+	return nil, fmt.Errorf("blink: %w", types.ErrUnsupported)
+}
+
+func (p *sampleProtocol) SetCharset(page int) ([]byte, error) {
+	if page < 0 || page > 255 {
+		return nil, errors.New("page must be between 0 and 255")
+	}
+	return []byte{0x1B, 0x74, byte(page)}, nil
+}
+
+func (p *sampleProtocol) SelfTest() ([]byte, error) {
+	return nil, fmt.Errorf("self-test: %w", types.ErrUnsupported)
+}
+
+// decodeSample decodes the cursor commands of sampleProtocol; swap swaps
+// their column and row, as a buggy decoder or protocol would.
+func decodeSample(swap bool) Decoder {
+	return DecoderFunc(func(cmd []byte) (Call, error) {
+		if len(cmd) != 4 || cmd[0] != 0x1F || cmd[1] != 0x24 {
+			return Call{}, types.ErrUnsupported
+		}
+		column, row := int(cmd[2]), int(cmd[3])
+		if swap {
+			column, row = row, column
+		}
+		return Call{"MoveCursor", []int{column, row}}, nil
+	})
+}
+
+var sampleConfig = Config{BrightnessLevels: 4, CodePages: []int{0, 19}}
+
+func TestConformingProtocolPasses(t *testing.T) {
+	cfg := sampleConfig
+	cfg.Decoder = decodeSample(false)
+	Run(t, &sampleProtocol{}, cfg)
+}
+
+func TestCheckFindsProblems(t *testing.T) {
+	tests := []struct {
+		name     string
+		protocol *sampleProtocol
+		cfg      Config
+		want     string
+	}{
+		{"nondeterministic", &sampleProtocol{counter: 1}, sampleConfig, "Clear(): not deterministic"},
+		{"empty command", &sampleProtocol{emptyFormFeed: true}, sampleConfig, "FormFeed(): empty command"},
+		{"invalid input", &sampleProtocol{looseCursor: true}, sampleConfig, "MoveCursor(0, 1): accepted invalid input"},
+		{"range too small", &sampleProtocol{}, Config{BrightnessLevels: 5, CodePages: []int{0}}, "SetBrightness(5): brightness level"},
+		{"unsupported", &sampleProtocol{}, Config{BrightnessLevels: 4, BlinkIntervals: []int{500}, CodePages: []int{0}}, "SetBlink(500): not supported"},
+		{"should be unsupported", &sampleProtocol{}, Config{BrightnessLevels: 4}, "SetCharset(0): error = <nil>"},
+		{"round trip", &sampleProtocol{}, Config{BrightnessLevels: 4, CodePages: []int{0}, Decoder: decodeSample(true)}, "MoveCursor(2, 1): 1F 24 02 01 decodes to MoveCursor(1, 2)"},
+	}
+	for _, tt := range tests {
+		errs := Check(tt.protocol, tt.cfg)
+		found := false
+		for _, err := range errs {
+			if strings.Contains(err.Error(), tt.want) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("%s: problems %v do not include %q", tt.name, errs, tt.want)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package govfd
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/corrreia/govfd/models/epson"
+	"github.com/corrreia/govfd/types"
+)
+
+// namedProtocol is basicProtocol under another name.
+type namedProtocol struct {
+	basicProtocol
+	name string
+}
+
+func (p *namedProtocol) GetName() string { return p.name }
+
+func TestRegisterProtocol(t *testing.T) {
+	protocol := &namedProtocol{name: "TEST-REGISTER"}
+	if err := RegisterProtocol(protocol); err != nil {
+		t.Fatalf("RegisterProtocol error: %v", err)
+	}
+	if got, ok := GetProtocol("TEST-REGISTER"); !ok || got != protocol {
+		t.Errorf("GetProtocol = %v, %v; want the registered protocol", got, ok)
+	}
+	if err := RegisterProtocol(&namedProtocol{name: "TEST-REGISTER"}); err == nil {
+		t.Error("registering a duplicate name succeeded")
+	}
+	if err := RegisterProtocol(&namedProtocol{name: types.ProtocolESCPOS}); err == nil {
+		t.Error("replacing a built-in protocol succeeded")
+	}
+	if err := RegisterProtocol(&namedProtocol{}); err == nil {
+		t.Error("registering a protocol without a name succeeded")
+	}
+	if err := RegisterProtocol(nil); err == nil {
+		t.Error("registering nil succeeded")
+	}
+}
+
+func TestRegisterModel(t *testing.T) {
+	profile := types.ModelProfile{
+		Name:            "In-house 16x1",
+		Columns:         16,
+		Rows:            1,
+		DefaultBaudRate: 19200,
+		CommandProtocol: types.ProtocolESCPOS,
+	}
+	if err := RegisterModel("TEST_INHOUSE", &profile); err != nil {
+		t.Fatalf("RegisterModel error: %v", err)
+	}
+	profile.Columns = 99 // The registry keeps its own copy
+
+	if cols, rows, ok := GetModelSpecs("TEST_INHOUSE"); !ok || cols != 16 || rows != 1 {
+		t.Errorf("GetModelSpecs = %d, %d, %v; want 16, 1, true", cols, rows, ok)
+	}
+	if opts, _ := GetModelDefaults("TEST_INHOUSE"); opts.BaudRate != 19200 {
+		t.Errorf("default baud rate = %d, want 19200", opts.BaudRate)
+	}
+	if _, err := Measure("TEST_INHOUSE", "Olá"); err != nil {
+		t.Errorf("Measure on registered model: %v", err)
+	}
+
+	invalid := map[string]struct {
+		model   types.Model
+		profile *types.ModelProfile
+	}{
+		"duplicate":   {"TEST_INHOUSE", &profile},
+		"built-in":    {types.ModelEpsonDMD110, &profile},
+		"no id":       {"", &profile},
+		"nil profile": {"TEST_NIL", nil},
+		"no size":     {"TEST_NOSIZE", &types.ModelProfile{CommandProtocol: types.ProtocolESCPOS}},
+		"no protocol": {"TEST_NOPROTO", &types.ModelProfile{Columns: 20, Rows: 2}},
+	}
+	for name, tt := range invalid {
+		if err := RegisterModel(tt.model, tt.profile); err == nil {
+			t.Errorf("%s: RegisterModel succeeded", name)
+		}
+	}
+}
+
+func TestModelProfilesAreCopied(t *testing.T) {
+	profile := switchableProfile()
+	if err := RegisterModel("TEST_COPIED", &profile); err != nil {
+		t.Fatalf("RegisterModel error: %v", err)
+	}
+	// Changing the registered profile...
+	profile.CodePages[types.CodePagePC437] = 7
+	profile.Emulations[1].CodePages[types.CodePagePC437] = 7
+
+	// ...or a returned one must not affect the registry
+	returned := mustProfile(t, "TEST_COPIED")
+	returned.Columns = 99
+	returned.CodePages[types.CodePagePC858] = 7
+	returned.Emulations[1].Select[3] = 'X'
+	returned.Emulations[1].CodePages[types.CodePagePC437] = 7
+
+	registered := mustProfile(t, "TEST_COPIED")
+	if registered.Columns != 20 {
+		t.Errorf("columns = %d, want 20", registered.Columns)
+	}
+	if page := registered.CodePages[types.CodePagePC437]; page != 0 {
+		t.Errorf("PC437 page = %d, want 0", page)
+	}
+	if page := registered.CodePages[types.CodePagePC858]; page != 19 {
+		t.Errorf("PC858 page = %d, want 19", page)
+	}
+	emulation := registered.Emulations[1]
+	if emulation.Select[3] != 0x32 || emulation.CodePages[types.CodePagePC437] != 0 {
+		t.Errorf("emulation changed: select % X, code pages %v", emulation.Select, emulation.CodePages)
+	}
+}
+
+func TestRegisterConcurrently(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			name := fmt.Sprintf("TEST-CONCURRENT-%02d", i)
+			if err := RegisterProtocol(&namedProtocol{name: name}); err != nil {
+				t.Error(err)
+			}
+			profile := &types.ModelProfile{Columns: 20, Rows: 2, CommandProtocol: name}
+			if err := RegisterModel(types.Model(name), profile); err != nil {
+				t.Error(err)
+			}
+			GetSupportedModels()
+			GetSupportedProtocols()
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 0; i < 16; i++ {
+		name := fmt.Sprintf("TEST-CONCURRENT-%02d", i)
+		if _, ok := GetProtocol(name); !ok {
+			t.Errorf("protocol %s missing", name)
+		}
+		if !IsModelSupported(types.Model(name)) {
+			t.Errorf("model %s missing", name)
+		}
+	}
+}
+
+func TestSupportedListsAreSorted(t *testing.T) {
+	RegisterProtocol(&namedProtocol{name: "AAA-FIRST"})
+	if protocols := GetSupportedProtocols(); !slices.IsSorted(protocols) {
+		t.Errorf("GetSupportedProtocols = %v, not sorted", protocols)
+	}
+	if models := GetSupportedModels(); !slices.IsSorted(models) {
+		t.Errorf("GetSupportedModels = %v, not sorted", models)
+	}
+}
+
+func TestDMD110VariantsHaveOwnCodePages(t *testing.T) {
+	base := reflect.ValueOf(epson.DMD110Profile.CodePages).UnsafePointer()
+	for _, variant := range []types.ModelProfile{
+		epson.DMD110SimplifiedChineseProfile, epson.DMD110TraditionalChineseProfile,
+		epson.DMD110KoreanProfile, epson.DMD110JapaneseProfile,
+	} {
+		if reflect.ValueOf(variant.CodePages).UnsafePointer() == base {
+			t.Errorf("%s shares its code page table with the DM-D110", variant.Name)
+		}
+		if !maps.Equal(variant.CodePages, epson.DMD110Profile.CodePages) {
+			t.Errorf("%s code pages = %v, want the DM-D110's", variant.Name, variant.CodePages)
+		}
+	}
+}
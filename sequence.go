@@ -0,0 +1,159 @@
+package govfd
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/corrreia/govfd/types"
+
+	"go.bug.st/serial"
+)
+
+// Sequence builds the bytes for a model without an open port, e.g. to embed
+// in a printer job or store as a macro:
+//
+//	data, err := govfd.NewSequence(types.ModelEpsonDMD110).
+//	    Clear().MoveTo(1, 2).Text("ação").Brightness(3).Bytes()
+//
+// Each step runs exactly as on a Display, including encoding and charset
+// switching, and the sequence tracks the cursor and character sets the
+// display will have. Playback assumes the display starts in its power-on
+// state; begin with Clear to be sure. The first error stops the sequence
+// and is returned by Bytes.
+type Sequence struct {
+	display *Display
+	buffer  *bufferPort
+	err     error
+}
+
+// NewSequence starts an empty sequence for model.
+func NewSequence(model types.Model) *Sequence {
+	s := &Sequence{buffer: &bufferPort{}}
+	profile, exists := GetModelProfile(model)
+	if !exists {
+		s.err = errors.New("unsupported VFD model: " + string(model))
+		return s
+	}
+	protocol, err := protocolForModel(profile)
+	if err != nil {
+		s.err = err
+		return s
+	}
+	encoder, err := newModelEncoder(protocol, profile)
+	if err != nil {
+		s.err = err
+		return s
+	}
+	s.display = &Display{
+		port:     s.buffer,
+		portName: "sequence",
+		columns:  profile.Columns,
+		rows:     profile.Rows,
+		protocol: protocol,
+		encoder:  encoder,
+		profile:  profile,
+	}
+	return s
+}
+
+// step runs fn on the sequence's display unless an earlier step failed.
+func (s *Sequence) step(fn func(d *Display) error) *Sequence {
+	if s.err == nil {
+		s.err = fn(s.display)
+	}
+	return s
+}
+
+// Clear initializes the display, as Display.Clear does.
+func (s *Sequence) Clear() *Sequence {
+	return s.step((*Display).Clear)
+}
+
+// FormFeed clears the screen, as Display.FormFeed does.
+func (s *Sequence) FormFeed() *Sequence {
+	return s.step((*Display).FormFeed)
+}
+
+// MoveTo moves the cursor to a 1-based position, as Display.SetCursor does.
+func (s *Sequence) MoveTo(column, row int) *Sequence {
+	return s.step(func(d *Display) error { return d.SetCursor(column, row) })
+}
+
+// Text writes text at the cursor, as Display.WriteText does.
+func (s *Sequence) Text(text string) *Sequence {
+	return s.step(func(d *Display) error { return d.WriteText(text) })
+}
+
+// Line replaces a whole row, as Display.WriteLine does.
+func (s *Sequence) Line(row int, text string) *Sequence {
+	return s.step(func(d *Display) error { return d.WriteLine(row, text) })
+}
+
+// Brightness sets the brightness level, as Display.SetBrightness does.
+func (s *Sequence) Brightness(level int) *Sequence {
+	return s.step(func(d *Display) error { return d.SetBrightness(level) })
+}
+
+// Blink sets the blink interval, as Display.SetBlink does.
+func (s *Sequence) Blink(ms int) *Sequence {
+	return s.step(func(d *Display) error { return d.SetBlink(ms) })
+}
+
+// DefineGlyph registers a user-defined character for r, as
+// Display.DefineGlyph does.
+func (s *Sequence) DefineGlyph(r rune, glyph types.Glyph) *Sequence {
+	return s.step(func(d *Display) error { return d.DefineGlyph(r, glyph) })
+}
+
+// Raw appends bytes unchanged, as Display.WriteRawBytes does. The sequence
+// cannot follow their effect on the display.
+func (s *Sequence) Raw(data []byte) *Sequence {
+	return s.step(func(d *Display) error { return d.WriteRawBytes(data) })
+}
+
+// Cursor returns the cursor position the display will have after the
+// sequence, (0, 0) if unknown.
+func (s *Sequence) Cursor() (int, int) {
+	if s.display == nil {
+		return 0, 0
+	}
+	return s.display.GetCursor()
+}
+
+// Err returns the error that stopped the sequence, if any.
+func (s *Sequence) Err() error {
+	return s.err
+}
+
+// Bytes returns the bytes of the sequence so far, or the error that
+// stopped it.
+func (s *Sequence) Bytes() ([]byte, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return bytes.Clone(s.buffer.data), nil
+}
+
+// bufferPort is a serial.Port that collects what is written to it.
+type bufferPort struct {
+	data []byte
+}
+
+func (p *bufferPort) Write(b []byte) (int, error) {
+	p.data = append(p.data, b...)
+	return len(b), nil
+}
+
+func (p *bufferPort) Read(b []byte) (int, error)                           { return 0, io.EOF }
+func (p *bufferPort) SetMode(mode *serial.Mode) error                      { return nil }
+func (p *bufferPort) Drain() error                                         { return nil }
+func (p *bufferPort) ResetInputBuffer() error                              { return nil }
+func (p *bufferPort) ResetOutputBuffer() error                             { return nil }
+func (p *bufferPort) SetDTR(dtr bool) error                                { return nil }
+func (p *bufferPort) SetRTS(rts bool) error                                { return nil }
+func (p *bufferPort) GetModemStatusBits() (*serial.ModemStatusBits, error) { return nil, nil }
+func (p *bufferPort) SetReadTimeout(t time.Duration) error                 { return nil }
+func (p *bufferPort) Close() error                                         { return nil }
+func (p *bufferPort) Break(t time.Duration) error                          { return nil }
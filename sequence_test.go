@@ -0,0 +1,83 @@
+package govfd
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/corrreia/govfd/commands/escpos"
+	"github.com/corrreia/govfd/types"
+)
+
+func TestSequenceMatchesDisplay(t *testing.T) {
+	data, err := NewSequence(types.ModelEpsonDMD110).
+		Clear().MoveTo(1, 2).Text("ação").Brightness(3).Bytes()
+	if err != nil {
+		t.Fatalf("Bytes error: %v", err)
+	}
+
+	d, port := newModelTestDisplay(t, types.ModelEpsonDMD110)
+	d.Clear()
+	d.SetCursor(1, 2)
+	d.WriteText("ação")
+	d.SetBrightness(3)
+	if !bytes.Equal(data, port.written) {
+		t.Errorf("sequence = % X, display wrote % X", data, port.written)
+	}
+
+	var commands []escpos.Command
+	for _, dc := range escpos.NewDecoder().Decode(data) {
+		commands = append(commands, dc.Command)
+	}
+	want := []escpos.Command{
+		escpos.Initialize{},
+		escpos.MoveCursor{Column: 1, Row: 2},
+		escpos.SelectCodeTable{Page: 3},
+		escpos.Text{Text: "ação"},
+		escpos.SetBrightness{Level: 3},
+	}
+	if !reflect.DeepEqual(commands, want) {
+		t.Errorf("sequence decodes to %v, want %v", commands, want)
+	}
+}
+
+func TestSequenceTracksState(t *testing.T) {
+	s := NewSequence(types.ModelEpsonDMD110).Clear().MoveTo(1, 2).Text("ação")
+	before, _ := s.Bytes()
+
+	// The code table selected for "ação" is still active
+	data, err := s.Text("pão").Bytes()
+	if err != nil {
+		t.Fatalf("Bytes error: %v", err)
+	}
+	if added := data[len(before):]; string(added) != "p\x84o" {
+		t.Errorf("second text added % X, want only the text", added)
+	}
+	if col, row := s.Cursor(); col != 8 || row != 2 {
+		t.Errorf("cursor = (%d,%d), want (8,2)", col, row)
+	}
+}
+
+func TestSequenceStopsAtFirstError(t *testing.T) {
+	s := NewSequence(types.ModelEpsonDMD110).Clear().MoveTo(21, 1).Text("lost")
+	if s.Err() == nil {
+		t.Fatal("MoveTo past the last column succeeded")
+	}
+	if data, err := s.Bytes(); err == nil || data != nil {
+		t.Errorf("Bytes = % X, %v; want nil and the error", data, err)
+	}
+
+	if _, err := NewSequence("NO_SUCH_MODEL").Clear().Bytes(); err == nil {
+		t.Error("sequence for an unknown model succeeded")
+	}
+}
+
+func TestSequenceUsesModelProtocol(t *testing.T) {
+	data, err := NewSequence(types.ModelMatrixOrbitalLK204).MoveTo(20, 4).Brightness(4).Bytes()
+	if err != nil {
+		t.Fatalf("Bytes error: %v", err)
+	}
+	if want := "\xfeG\x14\x04\xfe\x99\xff"; string(data) != want {
+		t.Errorf("sequence = % X, want % X", data, want)
+	}
+}
@@ -0,0 +1,168 @@
+package types
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// CodePage identifies a single-byte character code table independently of the
+// page number a particular display uses to select it.
+type CodePage string
+
+// Known character code tables
+const (
+	// PC437 - USA, Standard Europe
+	CodePagePC437 CodePage = "PC437"
+
+	// Katakana - Japanese half-width Katakana (JIS X 0201)
+	CodePageKatakana CodePage = "KATAKANA"
+
+	// PC850 - Multilingual Latin
+	CodePagePC850 CodePage = "PC850"
+
+	// PC860 - Portuguese
+	CodePagePC860 CodePage = "PC860"
+
+	// PC858 - Multilingual Latin with Euro
+	CodePagePC858 CodePage = "PC858"
+
+	// HD44780 - the fixed character ROM (A00) of HD44780-compatible modules:
+	// ASCII with '¥' and '→' in place of '\' and '~', plus half-width
+	// Katakana
+	CodePageHD44780 CodePage = "HD44780"
+)
+
+// CodePageTable maps the code tables a display supports to the page numbers
+// it expects in its "select code table" command.
+type CodePageTable map[CodePage]int
+
+// InternationalCharset identifies a national variant of ASCII that replaces a
+// handful of positions (# $ @ [ \ ] ^ ` { | } ~) with local characters.
+type InternationalCharset string
+
+// Known international character sets
+const (
+	InternationalUSA          InternationalCharset = "USA"
+	InternationalFrance       InternationalCharset = "FRANCE"
+	InternationalGermany      InternationalCharset = "GERMANY"
+	InternationalUK           InternationalCharset = "UK"
+	InternationalDenmarkI     InternationalCharset = "DENMARK_I"
+	InternationalSweden       InternationalCharset = "SWEDEN"
+	InternationalItaly        InternationalCharset = "ITALY"
+	InternationalSpainI       InternationalCharset = "SPAIN_I"
+	InternationalJapan        InternationalCharset = "JAPAN"
+	InternationalNorway       InternationalCharset = "NORWAY"
+	InternationalDenmarkII    InternationalCharset = "DENMARK_II"
+	InternationalSpainII      InternationalCharset = "SPAIN_II"
+	InternationalLatinAmerica InternationalCharset = "LATIN_AMERICA"
+	InternationalKorea        InternationalCharset = "KOREA"
+)
+
+// Substitution records a rune of the input text that is not shown as itself.
+type Substitution struct {
+	Offset      int    // byte offset of the rune in the input text
+	Rune        rune   // the original rune
+	Replacement string // what the display shows instead
+	Replaced    bool   // true if replaced with '?', false if transliterated
+}
+
+// Measurement describes how a text would look on a display: the columns it
+// occupies, the character sets it needs and what happens to runes the
+// display cannot show.
+type Measurement struct {
+	Cells         int                  // display columns occupied
+	CodePages     []CodePage           // code tables needed (none for plain ASCII)
+	DoubleByte    bool                 // shown in double-byte (Kanji) mode
+	International InternationalCharset // international set active for the text
+	Switches      []byte               // commands sent before the text to select the above
+	Substitutions []Substitution       // runes transliterated or replaced
+}
+
+// InvalidUTF8Policy controls how text that is not valid UTF-8 is handled.
+type InvalidUTF8Policy int
+
+const (
+	// InvalidUTF8Replace shows each run of invalid bytes as one '?'. This is the default.
+	InvalidUTF8Replace InvalidUTF8Policy = iota
+
+	// InvalidUTF8Reject fails on text that is not valid UTF-8.
+	InvalidUTF8Reject
+
+	// InvalidUTF8Latin1 reads each invalid byte as an ISO 8859-1 character.
+	InvalidUTF8Latin1
+
+	// InvalidUTF8CP1252 reads each invalid byte as a Windows-1252 character.
+	InvalidUTF8CP1252
+)
+
+// CharsetPolicy controls which code table an encoder selects for text.
+// The zero value keeps the current table whenever it covers the text and
+// otherwise picks the best table by the characters used.
+type CharsetPolicy struct {
+	// Pinned, if set, is the only code table used. Characters it lacks are
+	// shown through international sets or glyphs, or replaced.
+	Pinned CodePage
+
+	// Preferred lists code tables to try first, best first. Tables the model
+	// lacks are skipped.
+	Preferred []CodePage
+
+	// Locale (e.g. "pt-BR", "de") supplies Preferred from LocaleCodePagesFor
+	// when Preferred is empty.
+	Locale string
+
+	// SwitchToBest switches to the best table for each text even when the
+	// current one covers it. By default the current table is kept, saving
+	// a switch.
+	SwitchToBest bool
+}
+
+var (
+	// localeCodePages lists suitable code tables per language, best first.
+	localeCodePages = map[string][]CodePage{
+		"en": {CodePagePC437, CodePagePC858},
+		"pt": {CodePagePC860, CodePagePC858, CodePagePC850},
+		"es": {CodePagePC858, CodePagePC850, CodePagePC437},
+		"fr": {CodePagePC858, CodePagePC850, CodePagePC437},
+		"de": {CodePagePC858, CodePagePC850, CodePagePC437},
+		"it": {CodePagePC858, CodePagePC850, CodePagePC437},
+		"ja": {CodePageKatakana, CodePagePC437},
+	}
+	localeCodePagesMu sync.RWMutex
+)
+
+// LocaleCodePagesFor returns the suitable code tables for the language of a
+// locale such as "pt-BR", best first, or nil if there are none.
+func LocaleCodePagesFor(locale string) []CodePage {
+	localeCodePagesMu.RLock()
+	defer localeCodePagesMu.RUnlock()
+	return slices.Clone(localeCodePages[localeLanguage(locale)])
+}
+
+// RegisterLocaleCodePages adds the suitable code tables for a language such
+// as "pl", best first. It is safe to call concurrently; registering a
+// language twice is an error.
+func RegisterLocaleCodePages(language string, codePages []CodePage) error {
+	if language == "" || localeLanguage(language) != language {
+		return errors.New("language must be a lowercase code without region, e.g. \"pl\"")
+	}
+	if len(codePages) == 0 {
+		return errors.New("language " + language + ": no code pages")
+	}
+	localeCodePagesMu.Lock()
+	defer localeCodePagesMu.Unlock()
+	if _, exists := localeCodePages[language]; exists {
+		return errors.New("language already registered: " + language)
+	}
+	localeCodePages[language] = slices.Clone(codePages)
+	return nil
+}
+
+// localeLanguage returns the language part of a locale such as "pt-BR".
+func localeLanguage(locale string) string {
+	language, _, _ := strings.Cut(strings.ToLower(locale), "-")
+	language, _, _ = strings.Cut(language, "_")
+	return language
+}
@@ -0,0 +1,13 @@
+package types
+
+// CharsetSwitcher sends the character set commands an encoder needs to the
+// display, in whatever form the display's protocol uses. Each method returns
+// an error if the protocol lacks the command or sending it fails; encoders
+// update their record of the display state only when a call succeeds.
+type CharsetSwitcher interface {
+	SelectCodeTable(page int) error                                // Select a character code table
+	SetDoubleByteMode(enabled bool) error                          // Enter/leave double-byte (Kanji) mode
+	SelectInternationalCharset(charset InternationalCharset) error // Select an international character set
+	DefineGlyph(code byte, glyph Glyph) error                      // Download a user-defined glyph at code
+	CancelGlyph(code byte) error                                   // Restore the built-in character at code
+}
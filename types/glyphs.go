@@ -0,0 +1,15 @@
+package types
+
+// Glyph dimensions of user-defined characters on 5x7 dot displays.
+const (
+	GlyphWidth  = 5
+	GlyphHeight = 7
+)
+
+// Glyph is the dot bitmap of a user-defined character. Each entry is one row,
+// top to bottom; the low 5 bits hold the dots, with bit 4 the leftmost.
+//
+// Example (a check mark):
+//
+//	types.Glyph{0b00000, 0b00001, 0b00010, 0b10100, 0b01000, 0b00000, 0b00000}
+type Glyph [GlyphHeight]uint8
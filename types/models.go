@@ -9,6 +9,61 @@ type Model string
 const (
 	// Epson DM-D110 customer display
 	ModelEpsonDMD110 Model = "EPSON_DM_D110"
+
+	// Epson DM-D110 Asian variants with double-byte character support
+	ModelEpsonDMD110SimplifiedChinese  Model = "EPSON_DM_D110_SC"
+	ModelEpsonDMD110TraditionalChinese Model = "EPSON_DM_D110_TC"
+	ModelEpsonDMD110Korean             Model = "EPSON_DM_D110_KR"
+	ModelEpsonDMD110Japanese           Model = "EPSON_DM_D110_JP"
+
+	// Generic 20x2 pole display with the CD5220 command set
+	ModelGenericCD5220 Model = "GENERIC_CD5220"
+
+	// Generic 20x2 pole displays with line-only command sets
+	ModelGenericAedex  Model = "GENERIC_AEDEX"
+	ModelGenericDSP800 Model = "GENERIC_DSP800"
+
+	// Generic 20x2 pole display with DIP-selectable emulations
+	ModelGenericMultiEmulation Model = "GENERIC_MULTI_EMULATION"
+
+	// Wincor Nixdorf retail displays with the BA63 command set
+	ModelWincorBA63 Model = "WINCOR_BA63"
+	ModelWincorBA66 Model = "WINCOR_BA66"
+
+	// Logic Controls pole displays
+	ModelLogicControlsPD3000 Model = "LOGIC_CONTROLS_PD3000"
+	ModelLogicControlsLD9000 Model = "LOGIC_CONTROLS_LD9000"
+
+	// Noritake Itron CU-U series character VFD modules
+	ModelNoritakeCU20025 Model = "NORITAKE_CU20025"
+	ModelNoritakeCU20045 Model = "NORITAKE_CU20045"
+
+	// Matrix Orbital serial character modules
+	ModelMatrixOrbitalLK162 Model = "MATRIX_ORBITAL_LK162"
+	ModelMatrixOrbitalLK204 Model = "MATRIX_ORBITAL_LK204"
+	ModelMatrixOrbitalLK402 Model = "MATRIX_ORBITAL_LK402"
+)
+
+// DoubleByteCharset identifies the double-byte (Kanji) character set built
+// into an Asian model variant.
+type DoubleByteCharset string
+
+// Supported double-byte character sets
+const (
+	// No double-byte support (single-byte code pages only)
+	DoubleByteNone DoubleByteCharset = ""
+
+	// GB2312 - Simplified Chinese
+	DoubleByteGB2312 DoubleByteCharset = "GB2312"
+
+	// Big5 - Traditional Chinese
+	DoubleByteBig5 DoubleByteCharset = "BIG5"
+
+	// KS C 5601 - Korean
+	DoubleByteKSC5601 DoubleByteCharset = "KSC5601"
+
+	// Shift-JIS - Japanese
+	DoubleByteShiftJIS DoubleByteCharset = "SHIFT_JIS"
 )
 
 // ModelProfile contains all specifications and default settings for a VFD model.
@@ -28,9 +83,13 @@ type ModelProfile struct {
 	DefaultParity   serial.Parity
 	DefaultStopBits serial.StopBits
 
-	// Command protocol
+	// Command protocol (the default emulation)
 	CommandProtocol string
 
+	// Emulations the display can run, including the default, for displays
+	// with several command sets (nil means CommandProtocol only)
+	Emulations []Emulation
+
 	// Display capabilities
 	SupportsBrightness   bool
 	BrightnessLevels     int
@@ -38,6 +97,27 @@ type ModelProfile struct {
 	SupportsCharsetTable bool
 	SupportsSelfTest     bool
 
+	// Supported code tables and their device page numbers
+	// (nil means the standard ESC/POS numbering)
+	CodePages CodePageTable
+
+	// Double-byte character set of this variant (DoubleByteNone if unsupported)
+	DoubleByteCharset DoubleByteCharset
+
 	// Documentation reference
 	DocumentationURL string
 }
+
+// Emulation is a command protocol a multi-emulation display can run.
+type Emulation struct {
+	// Protocol is the registered name of the command protocol.
+	Protocol string
+
+	// Select is the command that switches the display to this emulation,
+	// or nil if only DIP switches or the setup menu select it.
+	Select []byte
+
+	// CodePages are the code tables under this emulation
+	// (nil means the profile's CodePages).
+	CodePages CodePageTable
+}
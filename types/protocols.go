@@ -1,12 +1,81 @@
 package types
 
+import "errors"
+
 // Protocol names as constants for reference
 const (
 	// ESC/POS protocol - most common for VFD displays
 	ProtocolESCPOS = "ESC/POS"
 
+	// CD5220 protocol - generic pole displays
+	ProtocolCD5220 = "CD5220"
+
+	// BA63 protocol - ANSI-style sequences of Wincor Nixdorf displays
+	ProtocolBA63 = "BA63"
+
+	// Logic Controls protocol - native commands of PD3000/LD9000 displays
+	ProtocolLogicControls = "LOGIC-CONTROLS"
+
+	// Aedex protocol - "!#n text" line commands of legacy pole displays
+	ProtocolAedex = "AEDEX"
+
+	// DSP800 protocol - ESC Q line commands of legacy pole displays
+	ProtocolDSP800 = "DSP800"
+
+	// Noritake protocol - Noritake Itron CU-U series modules
+	ProtocolNoritakeCU = "NORITAKE-CU"
+
+	// Matrix Orbital protocol - 0xFE-prefixed commands of LK/VK modules
+	ProtocolMatrixOrbital = "MATRIX-ORBITAL"
+
 	// Add more protocols here as support is added
 	// ProtocolCustom = "CUSTOM"
 	// ProtocolAPI = "API"
 	// ProtocolWebSocket = "WEBSOCKET"
 )
+
+// Protocol is the set of operations every command protocol implements.
+// It is documented, with its optional extensions, as govfd.Protocol.
+type Protocol interface {
+	// Protocol identification
+	GetName() string
+	GetDescription() string
+
+	// Display control operations
+	Clear() ([]byte, error)                     // Initialize/clear display
+	FormFeed() ([]byte, error)                  // Clear screen content
+	MoveCursor(column, row int) ([]byte, error) // Move cursor to position (1-based)
+
+	// Display settings
+	SetBrightness(level int) ([]byte, error) // Set brightness (1-4 typically)
+	SetBlink(intervalMs int) ([]byte, error) // Set cursor blink (0=off)
+	SetCharset(page int) ([]byte, error)     // Set character encoding table
+
+	// Utility operations
+	SelfTest() ([]byte, error) // Execute self-test
+}
+
+// ErrUnsupported is returned, possibly wrapped, by protocols for operations
+// they have no command for.
+var ErrUnsupported = errors.New("not supported")
+
+// DisplayMode selects what happens when text reaches the end of the screen.
+type DisplayMode string
+
+const (
+	// DisplayModeOverwrite wraps to the start of the screen, overwriting it
+	DisplayModeOverwrite DisplayMode = "overwrite"
+
+	// DisplayModeVerticalScroll scrolls the lines up, writing on the last line
+	DisplayModeVerticalScroll DisplayMode = "vertical-scroll"
+
+	// DisplayModeHorizontalScroll scrolls the current line to the left
+	DisplayModeHorizontalScroll DisplayMode = "horizontal-scroll"
+)
+
+// Status is what a display reports about itself.
+type Status struct {
+	Ready bool   // The display accepts commands
+	Fault bool   // The display reports a hardware fault
+	Raw   []byte // The reply as received
+}
@@ -36,8 +36,11 @@ type Display struct {
 	cursorRow    int
 	brightness   int
 	blinkMs      int
-	protocol     Protocol               // Command protocol for this display
-	encoder      *escpos.CharsetEncoder // Character encoding handler
+	protocol     Protocol            // Command protocol for this display
+	encoder      Encoder             // Character encoding handler
+	profile      *types.ModelProfile // Model profile at the opened size (nil for Open)
+
+	encodingPolicy EncodingPolicy // What to do with characters the display cannot show
 }
 
 // DefaultOptions returns commonly used defaults (9600 8N1).
@@ -54,37 +57,16 @@ func DefaultOptions() *Options {
 // This is the recommended way to open a VFD display as it automatically
 // configures the correct serial settings and dimensions for the specified model.
 func OpenModel(portName string, model types.Model) (*Display, error) {
-	if portName == "" {
-		return nil, errors.New("portName is required")
-	}
-
-	modelProfile, exists := GetModelProfile(model)
-	if !exists {
-		return nil, errors.New("unsupported VFD model: " + string(model))
-	}
-
-	opts, _ := GetModelDefaults(model)
-	display, err := Open(portName, opts)
-	if err != nil {
-		return nil, err
-	}
-
-	// Set the command protocol for this model
-	protocol, exists := GetProtocol(modelProfile.CommandProtocol)
-	if !exists {
-		return nil, errors.New("unsupported command protocol: " + modelProfile.CommandProtocol)
-	}
-	display.protocol = protocol
-
-	// Initialize character encoding
-	display.encoder = escpos.NewCharsetEncoder()
-
-	return display, nil
+	return OpenModelWithOptions(portName, model, nil)
 }
 
 // OpenModelWithOptions establishes a connection to a VFD using model defaults
 // but allows overriding specific options. Model defaults are used for any
 // options that are not explicitly set (zero values) in the provided opts.
+//
+// opts.Protocol selects one of the model's emulations. If the emulation has
+// a select command it is sent once the port is open; otherwise the display
+// must already be set to it, e.g. by DIP switch.
 func OpenModelWithOptions(portName string, model types.Model, opts *Options) (*Display, error) {
 	if portName == "" {
 		return nil, errors.New("portName is required")
@@ -122,20 +104,39 @@ func OpenModelWithOptions(portName string, model types.Model, opts *Options) (*D
 		}
 	}
 
-	display, err := Open(portName, opts)
+	// Resolve the command protocol of the chosen emulation at the chosen size
+	sized := *modelProfile
+	sized.Columns, sized.Rows = opts.Columns, opts.Rows
+	emulation, err := modelEmulation(&sized, opts.Protocol)
+	if err != nil {
+		return nil, err
+	}
+	emulated := emulationProfile(&sized, emulation)
+	protocol, err := protocolForModel(emulated)
 	if err != nil {
 		return nil, err
 	}
 
-	// Set the command protocol for this model
-	protocol, exists := GetProtocol(modelProfile.CommandProtocol)
-	if !exists {
-		return nil, errors.New("unsupported command protocol: " + modelProfile.CommandProtocol)
+	// Initialize character encoding for this emulation's code tables
+	encoder, err := newModelEncoder(protocol, emulated)
+	if err != nil {
+		return nil, err
+	}
+
+	display, err := Open(portName, opts)
+	if err != nil {
+		return nil, err
 	}
 	display.protocol = protocol
+	display.encoder = encoder
+	display.profile = &sized
 
-	// Initialize character encoding
-	display.encoder = escpos.NewCharsetEncoder()
+	if opts.Protocol != "" && emulation.Select != nil {
+		if err := display.writeBytes(emulation.Select); err != nil {
+			display.Close()
+			return nil, err
+		}
+	}
 
 	return display, nil
 }
@@ -200,3 +201,15 @@ func (d *Display) writeBytes(payload []byte) error {
 	_, err := d.port.Write(payload)
 	return err
 }
+
+// writeCommand writes a command that may change what the display shows and,
+// once it is written, applies it to a ShadowProtocol's shadow screen.
+func (d *Display) writeCommand(cmd []byte) error {
+	if err := d.writeBytes(cmd); err != nil {
+		return err
+	}
+	if protocol, ok := d.protocol.(ShadowProtocol); ok {
+		protocol.Commit()
+	}
+	return nil
+}